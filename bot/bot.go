@@ -1,6 +1,8 @@
 package bot
 
 import (
+	"context"
+
 	"github.com/coldze/primitives/custom_error"
 	"github.com/coldze/telebot/receive"
 	"github.com/coldze/telebot/send"
@@ -8,7 +10,10 @@ import (
 
 type UpdateCallback func(update *receive.UpdateType) ([]*send.SendType, custom_error.CustomError)
 
+// Bot receives updates and passes them to UpdateProcessor. Run blocks until ctx is cancelled or Stop is called,
+// then lets in-flight updates and sends complete before returning.
 type Bot interface {
+	Run(ctx context.Context) custom_error.CustomError
 	Send([]*send.SendType) custom_error.CustomError
 	Stop()
 }
@@ -16,3 +21,27 @@ type Bot interface {
 type UpdateProcessor interface {
 	Process(update *receive.UpdateType) custom_error.CustomError
 }
+
+// WebHookReplyProcessor is UpdateProcessor, that can return one of update's responses instead of sending it,
+// so web-hook handler writes it into response body.
+type WebHookReplyProcessor interface {
+	UpdateProcessor
+	ProcessWithReply(update *receive.UpdateType) (*send.SendType, custom_error.CustomError)
+}
+
+// UpdateDoneCallback is invoked, when update, passed to AsyncUpdateProcessor, is handled. err is set, if handling failed.
+type UpdateDoneCallback func(err custom_error.CustomError)
+
+// AsyncUpdateProcessor is UpdateProcessor, that handles updates in background. ProcessAsync invokes done, once update
+// is handled, unless it returns error itself.
+type AsyncUpdateProcessor interface {
+	UpdateProcessor
+	ProcessAsync(update *receive.UpdateType, done UpdateDoneCallback) custom_error.CustomError
+}
+
+// DrainableUpdateProcessor is UpdateProcessor, that handles accepted updates in background. Wait blocks until they are handled.
+// Bots call it on shutdown, so Run returns after queued updates are done.
+type DrainableUpdateProcessor interface {
+	UpdateProcessor
+	Wait()
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+const (
+	certificate_check_period = 10 * time.Second
+)
+
+// certificateReloader serves key pair from files and reloads it, when certificate file is modified (e.g. rotated).
+type certificateReloader struct {
+	mutex       sync.Mutex
+	certFile    string
+	keyFile     string
+	certificate *tls.Certificate
+	modTime     time.Time
+	checkedAt   time.Time
+}
+
+func (r *certificateReloader) reload() custom_error.CustomError {
+	info, err := os.Stat(r.certFile)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to stat certificate '%v'. Error: %v", r.certFile, err)
+	}
+	if r.certificate != nil && info.ModTime().Equal(r.modTime) {
+		return nil
+	}
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to load key pair '%v', '%v'. Error: %v", r.certFile, r.keyFile, err)
+	}
+	r.certificate = &certificate
+	r.modTime = info.ModTime()
+	return nil
+}
+
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	if now.Sub(r.checkedAt) < certificate_check_period {
+		return r.certificate, nil
+	}
+	r.checkedAt = now
+	customErr := r.reload()
+	if customErr != nil && r.certificate == nil {
+		return nil, customErr
+	}
+	// on reload failure keep serving previous certificate
+	return r.certificate, nil
+}
+
+func newCertificateReloader(certFile string, keyFile string) (*certificateReloader, custom_error.CustomError) {
+	reloader := &certificateReloader{
+		certFile:  certFile,
+		keyFile:   keyFile,
+		checkedAt: time.Now(),
+	}
+	customErr := reloader.reload()
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to load certificate.")
+	}
+	return reloader, nil
+}
@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/receive"
+	"github.com/coldze/telebot/send"
+	"github.com/coldze/telebot/send/requests"
+)
+
+const (
+	// action is shown for 5 seconds, so it's re-sent a bit earlier
+	CHAT_ACTION_REFRESH_PERIOD = 4 * time.Second
+)
+
+// chatActionKeeper re-sends chat action, until it is stopped.
+type chatActionKeeper struct {
+	logger  logs.Logger
+	sender  RequestSender
+	request []*send.SendType
+	cancel  context.CancelFunc
+	done    sync.WaitGroup
+}
+
+func (k *chatActionKeeper) send(ctx context.Context) {
+	for i := range k.request {
+		_, customErr := sendSingleResponse(ctx, k.sender, k.request[i])
+		if customErr != nil && ctx.Err() == nil {
+			k.logger.Warningf("Failed to send chat action. Error: %v", customErr)
+		}
+	}
+}
+
+func (k *chatActionKeeper) run(ctx context.Context) {
+	defer k.done.Done()
+	ticker := time.NewTicker(CHAT_ACTION_REFRESH_PERIOD)
+	defer ticker.Stop()
+	for {
+		k.send(ctx)
+		select {
+		case _ = <-ctx.Done():
+			return
+		case _ = <-ticker.C:
+		}
+	}
+}
+
+// stop cancels pending action request and waits for keeper to exit, so action is not sent after response.
+func (k *chatActionKeeper) stop() {
+	k.cancel()
+	k.done.Wait()
+}
+
+func startChatAction(factory *send.RequestFactory, sender RequestSender, chatID int64, action string, logger logs.Logger) (*chatActionKeeper, custom_error.CustomError) {
+	request, customErr := factory.NewSendChatAction(&requests.SendChatAction{
+		ChatID: chatID,
+		Action: action,
+	})
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to create chat action request.")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	keeper := &chatActionKeeper{
+		logger:  logger,
+		sender:  sender,
+		request: request,
+		cancel:  cancel,
+	}
+	keeper.done.Add(1)
+	go keeper.run(ctx)
+	return keeper, nil
+}
+
+// withChatAction shows action in chat of update, while handle runs. If update has no chat, handle is just invoked.
+func withChatAction(factory *send.RequestFactory, sender RequestSender, action string, logger logs.Logger, update *receive.UpdateType, handle func() ([]*send.SendType, custom_error.CustomError)) ([]*send.SendType, custom_error.CustomError) {
+	var msg *receive.MessageType
+	if update != nil {
+		msg = GetMessage(update)
+	}
+	if msg == nil || msg.Chat == nil {
+		return handle()
+	}
+	keeper, customErr := startChatAction(factory, sender, msg.Chat.ID, action, logger)
+	if customErr != nil {
+		logger.Warningf("Failed to start chat action. Error: %v", customErr)
+		return handle()
+	}
+	defer keeper.stop()
+	return handle()
+}
+
+// NewChatActionHandler wraps command handler, so action (e.g. requests.CHAT_ACTION_TYPING) is shown in chat
+// and refreshed every CHAT_ACTION_REFRESH_PERIOD, while handler runs.
+func NewChatActionHandler(handler CommandHandler, action string, factory *send.RequestFactory, sender RequestSender, logger logs.Logger) CommandHandler {
+	return func(command *CommandCallType) ([]*send.SendType, custom_error.CustomError) {
+		return withChatAction(factory, sender, action, logger, command.MetaInfo, func() ([]*send.SendType, custom_error.CustomError) {
+			return handler(command)
+		})
+	}
+}
+
+// NewChatActionCallback is NewChatActionHandler for update callbacks.
+func NewChatActionCallback(onUpdate UpdateCallback, action string, factory *send.RequestFactory, sender RequestSender, logger logs.Logger) UpdateCallback {
+	return func(update *receive.UpdateType) ([]*send.SendType, custom_error.CustomError) {
+		return withChatAction(factory, sender, action, logger, update, func() ([]*send.SendType, custom_error.CustomError) {
+			return onUpdate(update)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package bot
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/receive"
+)
+
+const (
+	OVERFLOW_BLOCK = iota + 1
+	OVERFLOW_REJECT
+)
+
+const (
+	DEFAULT_QUEUE_DEPTH = 100
+)
+
+// ConcurrentProcessorConfig configures ConcurrentUpdateProcessor. QueueDepth is per worker.
+// Overflow defines what happens when worker's queue is full: OVERFLOW_BLOCK (default) makes Process wait,
+// OVERFLOW_REJECT makes Process return error immediately.
+// OnPanic is invoked, when handler panics; the rest of updates are processed anyway.
+type ConcurrentProcessorConfig struct {
+	Workers    int
+	QueueDepth int
+	Overflow   int64
+	OnPanic    PanicCallback
+}
+
+// ConcurrentUpdateProcessor processes updates from different chats in parallel, while updates of the same chat
+// are always handled by the same worker in order of arrival.
+type ConcurrentUpdateProcessor struct {
+	logger   logs.Logger
+	sender   RequestSender
+	onUpdate UpdateCallback
+	overflow int64
+	onPanic  PanicCallback
+	queues   []chan *queuedUpdate
+	workers  sync.WaitGroup
+	mutex    sync.RWMutex
+	closed   bool
+	// inFlight counts updates, that are queued or being handled, idle is signalled, when it drops to zero
+	inFlightMutex sync.Mutex
+	inFlight      int
+	idle          *sync.Cond
+}
+
+type queuedUpdate struct {
+	update *receive.UpdateType
+	done   UpdateDoneCallback
+}
+
+func getUpdateKey(update *receive.UpdateType) int64 {
+	msg := GetMessage(update)
+	if msg != nil && msg.Chat != nil {
+		return msg.Chat.ID
+	}
+	if update.CallbackQuery != nil {
+		return update.CallbackQuery.From.ID
+	}
+	if update.InlineQuery != nil {
+		return update.InlineQuery.From.ID
+	}
+	if update.ChosenInlineResult != nil {
+		return update.ChosenInlineResult.From.ID
+	}
+	return update.ID
+}
+
+func (u *ConcurrentUpdateProcessor) Process(update *receive.UpdateType) custom_error.CustomError {
+	return u.ProcessAsync(update, nil)
+}
+
+// ProcessAsync queues update and invokes done (if set), once it is handled.
+func (u *ConcurrentUpdateProcessor) ProcessAsync(update *receive.UpdateType, done UpdateDoneCallback) custom_error.CustomError {
+	if update == nil {
+		return custom_error.MakeErrorf("Update is nil.")
+	}
+	queue := u.queues[uint64(getUpdateKey(update))%uint64(len(u.queues))]
+	item := &queuedUpdate{
+		update: update,
+		done:   done,
+	}
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	if u.closed {
+		return custom_error.MakeErrorf("Processor is closed. Update id '%d' rejected.", update.ID)
+	}
+	u.track(1)
+	if u.overflow != OVERFLOW_REJECT {
+		queue <- item
+		return nil
+	}
+	select {
+	case queue <- item:
+		return nil
+	default:
+		u.track(-1)
+		return custom_error.MakeErrorf("Update queue is full. Update id '%d' rejected.", update.ID)
+	}
+}
+
+func (u *ConcurrentUpdateProcessor) track(delta int) {
+	u.inFlightMutex.Lock()
+	defer u.inFlightMutex.Unlock()
+	u.inFlight += delta
+	if u.inFlight <= 0 {
+		u.idle.Broadcast()
+	}
+}
+
+// Wait blocks until queued updates are handled. Unlike Close, processor keeps accepting updates.
+func (u *ConcurrentUpdateProcessor) Wait() {
+	u.inFlightMutex.Lock()
+	defer u.inFlightMutex.Unlock()
+	for u.inFlight > 0 {
+		u.idle.Wait()
+	}
+}
+
+// QueueLength returns number of updates, that are waiting to be processed.
+func (u *ConcurrentUpdateProcessor) QueueLength() int {
+	length := 0
+	for i := range u.queues {
+		length += len(u.queues[i])
+	}
+	return length
+}
+
+// Close stops accepting updates and waits until queued ones are processed.
+func (u *ConcurrentUpdateProcessor) Close() {
+	u.mutex.Lock()
+	if u.closed {
+		u.mutex.Unlock()
+		return
+	}
+	u.closed = true
+	for i := range u.queues {
+		close(u.queues[i])
+	}
+	u.mutex.Unlock()
+	u.workers.Wait()
+}
+
+func (u *ConcurrentUpdateProcessor) processSingle(item *queuedUpdate) {
+	err := processSafely(u.logger, u.onPanic, item.update, func() custom_error.CustomError {
+		return handleUpdate(u.onUpdate, u.sender, item.update)
+	})
+	if err != nil {
+		u.logger.Errorf("Error has happened, while processing update id '%d'. Error: %v.", item.update.ID, err)
+	}
+	if item.done != nil {
+		item.done(err)
+	}
+	u.track(-1)
+}
+
+func (u *ConcurrentUpdateProcessor) work(queue chan *queuedUpdate) {
+	defer u.workers.Done()
+	for item := range queue {
+		u.processSingle(item)
+	}
+}
+
+func NewConcurrentUpdateProcessor(onUpdate UpdateCallback, sender RequestSender, config ConcurrentProcessorConfig, logger logs.Logger) *ConcurrentUpdateProcessor {
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	queueDepth := config.QueueDepth
+	if queueDepth <= 0 {
+		queueDepth = DEFAULT_QUEUE_DEPTH
+	}
+	processor := &ConcurrentUpdateProcessor{
+		logger:   logger,
+		sender:   sender,
+		onUpdate: onUpdate,
+		overflow: config.Overflow,
+		onPanic:  config.OnPanic,
+		queues:   make([]chan *queuedUpdate, workers),
+	}
+	processor.idle = sync.NewCond(&processor.inFlightMutex)
+	processor.workers.Add(workers)
+	for i := range processor.queues {
+		processor.queues[i] = make(chan *queuedUpdate, queueDepth)
+		go processor.work(processor.queues[i])
+	}
+	return processor
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"sync/atomic"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/receive"
+	"github.com/coldze/telebot/send"
+)
+
+// DedupProcessor skips updates, that were already processed (e.g. redelivered web-hook or re-polled update after restart).
+// Update, which processing has failed, is forgotten, so it is processed again, when redelivered.
+type DedupProcessor struct {
+	logger     logs.Logger
+	processor  UpdateProcessor
+	store      UpdateIDStore
+	duplicates int64
+}
+
+// isNew reports, if update should be processed. If store fails, update is processed: duplicate is better than loss.
+func (p *DedupProcessor) isNew(update *receive.UpdateType) bool {
+	added, customErr := p.store.Add(update.ID)
+	if customErr != nil {
+		p.logger.Errorf("Failed to check update id '%d' for duplicate. Error: %v", update.ID, customErr)
+		return true
+	}
+	if added {
+		return true
+	}
+	atomic.AddInt64(&p.duplicates, 1)
+	p.logger.Warningf("Duplicate update id '%d' skipped.", update.ID)
+	return false
+}
+
+func (p *DedupProcessor) forget(update *receive.UpdateType) {
+	customErr := p.store.Remove(update.ID)
+	if customErr != nil {
+		p.logger.Errorf("Failed to forget update id '%d'. Error: %v", update.ID, customErr)
+	}
+}
+
+func (p *DedupProcessor) Process(update *receive.UpdateType) custom_error.CustomError {
+	if update == nil {
+		return custom_error.MakeErrorf("Update is nil.")
+	}
+	if !p.isNew(update) {
+		return nil
+	}
+	customErr := p.processor.Process(update)
+	if customErr != nil {
+		p.forget(update)
+		return custom_error.NewErrorf(customErr, "Failed to process update.")
+	}
+	return nil
+}
+
+func (p *DedupProcessor) ProcessWithReply(update *receive.UpdateType) (*send.SendType, custom_error.CustomError) {
+	if update == nil {
+		return nil, custom_error.MakeErrorf("Update is nil.")
+	}
+	if !p.isNew(update) {
+		return nil, nil
+	}
+	reply, customErr := processWithReply(p.processor, update)
+	if customErr != nil {
+		p.forget(update)
+		return nil, custom_error.NewErrorf(customErr, "Failed to process update.")
+	}
+	return reply, nil
+}
+
+// ProcessAsync passes update to processor asynchronously, if it supports it. done is invoked right away for duplicates.
+func (p *DedupProcessor) ProcessAsync(update *receive.UpdateType, done UpdateDoneCallback) custom_error.CustomError {
+	if update == nil {
+		return custom_error.MakeErrorf("Update is nil.")
+	}
+	if !p.isNew(update) {
+		done(nil)
+		return nil
+	}
+	customErr := processAsync(p.processor, update, func(err custom_error.CustomError) {
+		if err != nil {
+			p.forget(update)
+			done(custom_error.NewErrorf(err, "Failed to process update."))
+			return
+		}
+		done(nil)
+	})
+	if customErr != nil {
+		p.forget(update)
+		return custom_error.NewErrorf(customErr, "Failed to process update.")
+	}
+	return nil
+}
+
+func (p *DedupProcessor) Wait() {
+	waitProcessed(p.processor)
+}
+
+// Duplicates returns number of skipped duplicate updates.
+func (p *DedupProcessor) Duplicates() int64 {
+	return atomic.LoadInt64(&p.duplicates)
+}
+
+// NewDedupProcessor wraps processor with duplicate suppression. If store is nil, last DEFAULT_UPDATE_ID_WINDOW ids are kept in memory.
+func NewDedupProcessor(processor UpdateProcessor, store UpdateIDStore, logger logs.Logger) *DedupProcessor {
+	if store == nil {
+		store = NewMemoryUpdateIDStore(DEFAULT_UPDATE_ID_WINDOW)
+	}
+	return &DedupProcessor{
+		logger:    logger,
+		processor: processor,
+		store:     store,
+	}
+}
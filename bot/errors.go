@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/receive"
+)
+
+const (
+	DEFAULT_RETRY_AFTER = time.Second
+)
+
+// APIError is returned, when Bot API rejected request. ErrorCode and Description are taken from response,
+// Parameters are set, if API provided them (e.g. migrate_to_chat_id).
+type APIError struct {
+	custom_error.CustomError
+	ErrorCode   int64
+	Description string
+	Parameters  *receive.ResponseParameters
+}
+
+// TooManyRequestsError is returned, when Bot API responded with 429. Attempts is set by retrying sender,
+// when retry budget is exhausted.
+type TooManyRequestsError struct {
+	*APIError
+	RetryAfter time.Duration
+	Attempts   int
+}
+
+// AsAPIError extracts APIError from errors, returned by this package.
+func AsAPIError(err error) (*APIError, bool) {
+	switch v := err.(type) {
+	case *APIError:
+		return v, v != nil
+	case *TooManyRequestsError:
+		if v == nil {
+			return nil, false
+		}
+		return v.APIError, v.APIError != nil
+	}
+	return nil, false
+}
+
+func hasErrorCode(err error, code int64) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.ErrorCode == code
+}
+
+func hasDescription(err error, code int64, description string) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.ErrorCode != code {
+		return false
+	}
+	return strings.Contains(strings.ToLower(apiErr.Description), description)
+}
+
+func IsBadRequest(err error) bool {
+	return hasErrorCode(err, http.StatusBadRequest)
+}
+
+// IsForbidden reports, that bot can't write to chat (e.g. it was blocked by user or kicked from group).
+func IsForbidden(err error) bool {
+	return hasErrorCode(err, http.StatusForbidden)
+}
+
+func IsTooManyRequests(err error) bool {
+	return hasErrorCode(err, http.StatusTooManyRequests)
+}
+
+// IsConflict reports, that updates are already consumed elsewhere: other instance polls with the same token
+// or web-hook is set.
+func IsConflict(err error) bool {
+	return hasErrorCode(err, http.StatusConflict)
+}
+
+// IsWebHookActiveConflict reports, that getUpdates was rejected, because web-hook is set for bot.
+func IsWebHookActiveConflict(err error) bool {
+	return hasDescription(err, http.StatusConflict, "webhook is active")
+}
+
+func IsChatNotFound(err error) bool {
+	return hasDescription(err, http.StatusBadRequest, "chat not found")
+}
+
+func IsMessageNotModified(err error) bool {
+	return hasDescription(err, http.StatusBadRequest, "message is not modified")
+}
+
+// GetMigrateToChatID returns new chat ID, if request failed, because group was upgraded to supergroup.
+func GetMigrateToChatID(err error) (int64, bool) {
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.Parameters == nil || apiErr.Parameters.MigrateToChatID == 0 {
+		return 0, false
+	}
+	return apiErr.Parameters.MigrateToChatID, true
+}
+
+func wrapAPIErrorf(apiErr *APIError, format string, args ...interface{}) *APIError {
+	return &APIError{
+		CustomError: custom_error.NewErrorf(apiErr.CustomError, format, args...),
+		ErrorCode:   apiErr.ErrorCode,
+		Description: apiErr.Description,
+		Parameters:  apiErr.Parameters,
+	}
+}
+
+// wrapErrorf behaves like custom_error.NewErrorf, but keeps typed errors of this package, so callers can inspect them.
+func wrapErrorf(err custom_error.CustomError, format string, args ...interface{}) custom_error.CustomError {
+	switch v := err.(type) {
+	case *APIError:
+		return wrapAPIErrorf(v, format, args...)
+	case *TooManyRequestsError:
+		return &TooManyRequestsError{
+			APIError:   wrapAPIErrorf(v.APIError, format, args...),
+			RetryAfter: v.RetryAfter,
+			Attempts:   v.Attempts,
+		}
+	}
+	return custom_error.NewErrorf(err, format, args...)
+}
+
+func newResponseError(reply *http.Response, body []byte) custom_error.CustomError {
+	var result receive.SendResult
+	err := json.Unmarshal(body, &result)
+	if err != nil {
+		result = receive.SendResult{}
+	}
+	apiErr := &APIError{
+		CustomError: custom_error.MakeErrorf("Responded bad status: %s. Body: %s", reply.Status, string(body)),
+		ErrorCode:   result.ErrorCode,
+		Description: http.StatusText(reply.StatusCode),
+		Parameters:  result.Parameters,
+	}
+	if apiErr.ErrorCode == 0 {
+		apiErr.ErrorCode = int64(reply.StatusCode)
+	}
+	if result.Description != nil {
+		apiErr.Description = *result.Description
+	}
+	if apiErr.ErrorCode != http.StatusTooManyRequests {
+		return apiErr
+	}
+	retryAfter := DEFAULT_RETRY_AFTER
+	if result.Parameters != nil && result.Parameters.RetryAfter > 0 {
+		retryAfter = time.Duration(result.Parameters.RetryAfter) * time.Second
+	}
+	return &TooManyRequestsError{
+		APIError:   apiErr,
+		RetryAfter: retryAfter,
+	}
+}
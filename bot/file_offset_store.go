@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+type fileOffsetStore struct {
+	mutex sync.Mutex
+	path  string
+}
+
+func (s *fileOffsetStore) Load() (int64, custom_error.CustomError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, custom_error.MakeErrorf("Failed to read offset file '%v'. Error: %v", s.path, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if len(value) <= 0 {
+		return 0, nil
+	}
+	updateID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, custom_error.MakeErrorf("Failed to parse offset file '%v'. Error: %v", s.path, err)
+	}
+	return updateID, nil
+}
+
+// Commit writes offset to temporary file and renames it, so file always contains complete value.
+func (s *fileOffsetStore) Commit(updateID int64) custom_error.CustomError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	file, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to create temporary offset file. Error: %v", err)
+	}
+	tmpPath := file.Name()
+	_, err = file.WriteString(strconv.FormatInt(updateID, 10))
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return custom_error.MakeErrorf("Failed to write offset file '%v'. Error: %v", tmpPath, err)
+	}
+	err = os.Rename(tmpPath, s.path)
+	if err != nil {
+		os.Remove(tmpPath)
+		return custom_error.MakeErrorf("Failed to replace offset file '%v'. Error: %v", s.path, err)
+	}
+	return nil
+}
+
+// NewFileOffsetStore creates store, that keeps offset in file at path.
+func NewFileOffsetStore(path string) OffsetStore {
+	return &fileOffsetStore{
+		path: path,
+	}
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/receive"
+	"github.com/coldze/telebot/send"
+)
+
+// MigrationCallback is invoked, when group fromChatID was upgraded to supergroup toChatID.
+// It can be invoked more than once for the same migration, so it should be idempotent.
+type MigrationCallback func(fromChatID int64, toChatID int64)
+
+type migratingSender struct {
+	sender    RequestSender
+	onMigrate MigrationCallback
+	logger    logs.Logger
+}
+
+func getChatIDInt(chatID interface{}) (int64, bool) {
+	switch v := chatID.(type) {
+	case int64:
+		return v, true
+	case string:
+		res, err := strconv.ParseInt(v, 10, 64)
+		return res, err == nil
+	}
+	return 0, false
+}
+
+func (s *migratingSender) SendRequest(ctx context.Context, message *send.SendType) ([]byte, custom_error.CustomError) {
+	res, customErr := s.sender.SendRequest(ctx, message)
+	newChatID, ok := GetMigrateToChatID(customErr)
+	if !ok || message == nil || message.ChatID == nil {
+		return res, customErr
+	}
+	if message.NoMigrationResend {
+		s.logger.Warningf("Request to message of chat '%v' failed, because chat migrated to '%d'. Request is not resent.", message.ChatID, newChatID)
+		return res, customErr
+	}
+	if message.FromChatID != nil {
+		s.logger.Warningf("Request from chat '%v' to chat '%v' failed, because one of them migrated to '%d'. Request is not resent.", message.FromChatID, message.ChatID, newChatID)
+		return res, customErr
+	}
+	oldChatID, ok := getChatIDInt(message.ChatID)
+	if ok && s.onMigrate != nil {
+		s.onMigrate(oldChatID, newChatID)
+	}
+	migrated, migrateErr := message.WithChatID(newChatID)
+	if migrateErr != nil {
+		s.logger.Errorf("Failed to migrate request from chat '%v' to '%d'. Error: %v", message.ChatID, newChatID, migrateErr)
+		return nil, customErr
+	}
+	s.logger.Infof("Chat '%v' migrated to '%d'. Resending request.", message.ChatID, newChatID)
+	return s.sender.SendRequest(ctx, migrated)
+}
+
+// NewMigratingSender creates sender, that resends requests, failed because of group-to-supergroup migration, to new chat
+// and reports migration through onMigrate. Forward and copy requests are not resent, as migrated chat may be the source one,
+// neither are requests, that refer to existing message (see send.SendType.NoMigrationResend).
+func NewMigratingSender(sender RequestSender, onMigrate MigrationCallback, logger logs.Logger) RequestSender {
+	return &migratingSender{
+		sender:    sender,
+		onMigrate: onMigrate,
+		logger:    logger,
+	}
+}
+
+type migrationProcessor struct {
+	processor UpdateProcessor
+	onMigrate MigrationCallback
+}
+
+func (p *migrationProcessor) checkMigration(update *receive.UpdateType) {
+	if p.onMigrate == nil {
+		return
+	}
+	msg := GetMessage(update)
+	if msg != nil && msg.Chat != nil {
+		if msg.MigrateToChatID != 0 {
+			p.onMigrate(msg.Chat.ID, msg.MigrateToChatID)
+		}
+		if msg.MigrateFromChatID != 0 {
+			p.onMigrate(msg.MigrateFromChatID, msg.Chat.ID)
+		}
+	}
+}
+
+func (p *migrationProcessor) Process(update *receive.UpdateType) custom_error.CustomError {
+	p.checkMigration(update)
+	return p.processor.Process(update)
+}
+
+func (p *migrationProcessor) ProcessWithReply(update *receive.UpdateType) (*send.SendType, custom_error.CustomError) {
+	p.checkMigration(update)
+	return processWithReply(p.processor, update)
+}
+
+func (p *migrationProcessor) ProcessAsync(update *receive.UpdateType, done UpdateDoneCallback) custom_error.CustomError {
+	p.checkMigration(update)
+	return processAsync(p.processor, update, done)
+}
+
+func (p *migrationProcessor) Wait() {
+	waitProcessed(p.processor)
+}
+
+// NewMigrationProcessor creates processor, that reports migrations, announced by incoming service messages, through onMigrate
+// and then passes update to processor.
+func NewMigrationProcessor(processor UpdateProcessor, onMigrate MigrationCallback) UpdateProcessor {
+	return &migrationProcessor{
+		processor: processor,
+		onMigrate: onMigrate,
+	}
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+)
+
+// OffsetStore keeps id of the last processed update, so polling bot resumes from it after restart.
+// Load returns 0, if nothing was committed yet.
+type OffsetStore interface {
+	Load() (int64, custom_error.CustomError)
+	Commit(updateID int64) custom_error.CustomError
+}
+
+type memoryOffsetStore struct {
+	mutex    sync.Mutex
+	updateID int64
+}
+
+func (s *memoryOffsetStore) Load() (int64, custom_error.CustomError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.updateID, nil
+}
+
+func (s *memoryOffsetStore) Commit(updateID int64) custom_error.CustomError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.updateID = updateID
+	return nil
+}
+
+// offsetTracker commits update id, once it and all updates, received before it, are processed,
+// so updates, handled asynchronously, are not committed before they are done. offset is the last id,
+// that is done together with all before it, so getUpdates doesn't confirm updates, that are still in progress.
+type offsetTracker struct {
+	mutex     sync.Mutex
+	logger    logs.Logger
+	store     OffsetStore
+	confirmed int64
+	pending   []int64
+	done      map[int64]bool
+}
+
+// reset starts tracking from updateID, e.g. loaded from store, when polling starts.
+func (t *offsetTracker) reset(updateID int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.confirmed = updateID
+	t.pending = nil
+	t.done = make(map[int64]bool)
+}
+
+func (t *offsetTracker) offset() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.confirmed
+}
+
+// add starts tracking of update. It returns false, if update is already done or still in progress,
+// i.e. it was received again, because offset didn't pass it yet.
+func (t *offsetTracker) add(updateID int64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if updateID <= t.confirmed {
+		return false
+	}
+	for _, pendingID := range t.pending {
+		if pendingID == updateID {
+			return false
+		}
+	}
+	t.pending = append(t.pending, updateID)
+	return true
+}
+
+// complete marks update as processed. Failed update is not committed itself, but doesn't hold back the ones after it.
+func (t *offsetTracker) complete(updateID int64, ok bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.done[updateID] = ok
+	commitID := int64(0)
+	for len(t.pending) > 0 {
+		processed, finished := t.done[t.pending[0]]
+		if !finished {
+			break
+		}
+		if processed {
+			commitID = t.pending[0]
+		}
+		t.confirmed = t.pending[0]
+		delete(t.done, t.pending[0])
+		t.pending = t.pending[1:]
+	}
+	if commitID <= 0 {
+		return
+	}
+	customErr := t.store.Commit(commitID)
+	if customErr != nil {
+		t.logger.Errorf("Failed to commit update id '%d'. Error: %v.", commitID, customErr)
+	}
+}
+
+func newOffsetTracker(store OffsetStore, logger logs.Logger) *offsetTracker {
+	return &offsetTracker{
+		logger: logger,
+		store:  store,
+		done:   make(map[int64]bool),
+	}
+}
+
+// NewMemoryOffsetStore creates store, that keeps offset only for lifetime of process.
+func NewMemoryOffsetStore() OffsetStore {
+	return &memoryOffsetStore{}
+}
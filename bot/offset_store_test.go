@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+)
+
+type recordingOffsetStore struct {
+	commits []int64
+}
+
+func (s *recordingOffsetStore) Load() (int64, custom_error.CustomError) {
+	return 0, nil
+}
+
+func (s *recordingOffsetStore) Commit(updateID int64) custom_error.CustomError {
+	s.commits = append(s.commits, updateID)
+	return nil
+}
+
+func newTestOffsetTracker(updateIDs ...int64) (*offsetTracker, *recordingOffsetStore) {
+	store := &recordingOffsetStore{}
+	tracker := newOffsetTracker(store, logs.NewStdLogger())
+	tracker.reset(10)
+	for _, updateID := range updateIDs {
+		tracker.add(updateID)
+	}
+	return tracker, store
+}
+
+func TestOffsetTrackerCommitsInOrder(t *testing.T) {
+	tracker, store := newTestOffsetTracker(11, 12, 15)
+	tracker.complete(15, true)
+	tracker.complete(12, true)
+	if len(store.commits) != 0 || tracker.offset() != 10 {
+		t.Fatalf("committed before earlier update is done: commits %v, offset %d", store.commits, tracker.offset())
+	}
+	tracker.complete(11, true)
+	if len(store.commits) != 1 || store.commits[0] != 15 || tracker.offset() != 15 {
+		t.Fatalf("unexpected commits %v, offset %d", store.commits, tracker.offset())
+	}
+}
+
+func TestOffsetTrackerSkipsFailedUpdates(t *testing.T) {
+	tracker, store := newTestOffsetTracker(11, 12, 13)
+	tracker.complete(11, true)
+	tracker.complete(13, false)
+	tracker.complete(12, true)
+	if len(store.commits) != 2 || store.commits[0] != 11 || store.commits[1] != 12 {
+		t.Fatalf("unexpected commits %v", store.commits)
+	}
+	if tracker.offset() != 13 {
+		t.Fatalf("failed update holds back offset: %d", tracker.offset())
+	}
+}
+
+func TestOffsetTrackerRejectsKnownUpdates(t *testing.T) {
+	tracker, _ := newTestOffsetTracker(11, 12)
+	if tracker.add(10) || tracker.add(12) {
+		t.Fatalf("update, that is done or in progress, is added again")
+	}
+	tracker.complete(11, true)
+	if tracker.add(11) || !tracker.add(13) {
+		t.Fatalf("unexpected result of add after completion")
+	}
+}
@@ -1,6 +1,8 @@
 package bot
 
 import (
+	"context"
+	"net/http"
 	"runtime/debug"
 	"time"
 
@@ -10,87 +12,281 @@ import (
 	"github.com/coldze/telebot/send"
 )
 
+const (
+	CONFLICT_POLICY_RETRY = iota + 1
+	CONFLICT_POLICY_STOP
+	CONFLICT_POLICY_STANDBY
+)
+
+const (
+	DEFAULT_POLLING_MIN_BACKOFF = time.Second
+	DEFAULT_POLLING_MAX_BACKOFF = time.Minute
+	DEFAULT_POLLING_TIMEOUT     = 30 * time.Second
+	DEFAULT_POLLING_MIN_PERIOD  = 100 * time.Millisecond
+)
+
+// PollingConfig configures long polling. Timeout is passed to getUpdates as server-side timeout,
+// so client's http timeout (if any) must be greater than it. Timeout under a second is replaced with DEFAULT_POLLING_TIMEOUT.
+// OffsetStore is read on start and updated after each successfully processed update, so updates, that were
+// in progress, when process stopped, are processed again after restart. In-memory store is used, if it's not set.
+// With asynchronous processors (see AsyncUpdateProcessor, e.g. ConcurrentUpdateProcessor) update is committed,
+// once its handler is done and all updates before it are done as well.
+// Conflicts (other instance polls with the same token or web-hook is set, see IsConflict) are reported to OnConflict
+// and handled according to ConflictPolicy: CONFLICT_POLICY_RETRY (default) backs off as on any other error,
+// CONFLICT_POLICY_STOP makes Run return conflict error, CONFLICT_POLICY_STANDBY makes bot stand by without consuming
+// updates. Standby bot doesn't call getUpdates, as it would take updates from the active instance: it checks once
+// per MaxBackoff, if conflicting web-hook is deleted, and resumes polling, when it is gone or Resume is signalled.
+// If the other instance polls, only Resume makes bot poll again. With default policy Run deletes web-hook before
+// polling, with the other two web-hook, that is set (e.g. by other deployment), is kept and treated as conflict.
+// OnPanic is invoked, when processor panics, while processing update.
+type PollingConfig struct {
+	Timeout        time.Duration
+	Limit          int64
+	AllowedUpdates []string
+	MinBackoff     time.Duration
+	MaxBackoff     time.Duration
+	OffsetStore    OffsetStore
+	ConflictPolicy int64
+	OnConflict     ConflictCallback
+	Resume         <-chan struct{}
+	OnPanic        PanicCallback
+}
+
+// ConflictCallback is invoked with error, getUpdates was rejected with, when updates are consumed elsewhere.
+type ConflictCallback func(err custom_error.CustomError)
+
 type pollingBot struct {
-	stopBot         chan struct{}
+	state           runState
 	logger          logs.Logger
 	factory         *send.RequestFactory
+	sender          RequestSender
 	period          time.Duration
+	timeout         int64
+	limit           int64
+	allowedUpdates  []string
+	minBackoff      time.Duration
+	maxBackoff      time.Duration
+	offsetStore     OffsetStore
+	offsets         *offsetTracker
+	conflictPolicy  int64
+	onConflict      ConflictCallback
+	resume          <-chan struct{}
+	onPanic         PanicCallback
 	updateProcessor UpdateProcessor
 }
 
 func (b *pollingBot) Stop() {
-	b.stopBot <- struct{}{}
+	b.state.stop()
 }
 
 func (b *pollingBot) Send(msg []*send.SendType) custom_error.CustomError {
-	_, err := sendResponse(msg)
+	err := b.state.beginSend()
 	if err != nil {
 		return custom_error.NewErrorf(err, "Failed to send")
 	}
+	defer b.state.endSend()
+	err = sendWithCallbacks(context.Background(), b.sender, msg)
+	if err != nil {
+		return wrapErrorf(err, "Failed to send")
+	}
 	return nil
 }
 
-func (b *pollingBot) sendRequests(messages []*send.SendType) custom_error.CustomError {
+func (b *pollingBot) sendRequests(ctx context.Context, messages []*send.SendType) custom_error.CustomError {
 	var err custom_error.CustomError
 	for i := range messages {
-		_, err = sendRequest(messages[i])
+		_, err = b.sender.SendRequest(ctx, messages[i])
 		if err != nil {
-			return custom_error.NewErrorf(err, "Failed to send request")
+			return wrapErrorf(err, "Failed to send request")
 		}
 	}
 	return nil
 }
 
-func (b *pollingBot) run() {
-	go func() {
-		unsubscribe, err := b.factory.NewUnsubscribe()
-		if err != nil {
-			b.logger.Errorf("Failed to create unsubscribe request. Error: %v.", err)
-		} else {
-			err = b.sendRequests(unsubscribe)
-			if err != nil {
-				b.logger.Errorf("Failed to unsubscribe. Error: %v.", err)
+func (b *pollingBot) nextBackoff(current time.Duration) time.Duration {
+	if current <= 0 {
+		return b.minBackoff
+	}
+	next := current * 2
+	if next > b.maxBackoff {
+		return b.maxBackoff
+	}
+	return next
+}
+
+// keepsWebHook reports, whether web-hook, set for bot, must be kept and reported as conflict instead of being deleted.
+func (b *pollingBot) keepsWebHook() bool {
+	return b.conflictPolicy == CONFLICT_POLICY_STOP || b.conflictPolicy == CONFLICT_POLICY_STANDBY
+}
+
+func (b *pollingBot) unsubscribe(ctx context.Context) custom_error.CustomError {
+	unsubscribe, err := b.factory.NewUnsubscribe()
+	if err != nil {
+		return custom_error.NewErrorf(err, "Failed to create unsubscribe request.")
+	}
+	err = b.sendRequests(ctx, unsubscribe)
+	if err != nil {
+		return wrapErrorf(err, "Failed to unsubscribe.")
+	}
+	return nil
+}
+
+// checkWebHook returns conflict error, if web-hook is set for bot.
+func (b *pollingBot) checkWebHook(ctx context.Context) custom_error.CustomError {
+	info, err := GetWebHookInfo(ctx, b.factory, b.sender)
+	if err != nil {
+		return wrapErrorf(err, "Failed to check web-hook.")
+	}
+	if len(info.URL) <= 0 {
+		return nil
+	}
+	return &APIError{
+		CustomError: custom_error.MakeErrorf("Web-hook '%v' is set for bot.", info.URL),
+		ErrorCode:   http.StatusConflict,
+		Description: "Conflict: webhook is active",
+	}
+}
+
+// standBy waits until Resume is signalled or, if conflict is caused by web-hook, until web-hook is deleted.
+// It returns false, if ctx is cancelled first.
+func (b *pollingBot) standBy(ctx context.Context, webHook bool) bool {
+	for {
+		timer := time.NewTimer(b.maxBackoff)
+		select {
+		case _ = <-ctx.Done():
+			timer.Stop()
+			return false
+		case _ = <-b.resume:
+			timer.Stop()
+			return true
+		case _ = <-timer.C:
+		}
+		if !webHook {
+			continue
+		}
+		err := b.checkWebHook(ctx)
+		if err == nil {
+			return true
+		}
+		if !IsConflict(err) {
+			b.logger.Warningf("Failed to check web-hook, while standing by. Error: %v.", err)
+		}
+	}
+}
+
+func (b *pollingBot) Run(ctx context.Context) custom_error.CustomError {
+	runCtx, customErr := b.state.start(ctx)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to start polling.")
+	}
+	defer b.state.finish()
+	// updates, queued by asynchronous processor, are handled before run is finished
+	defer waitProcessed(b.updateProcessor)
+
+	webHookChecked := !b.keepsWebHook()
+	if webHookChecked {
+		customErr = b.unsubscribe(runCtx)
+		if customErr != nil {
+			if runCtx.Err() != nil {
+				return nil
 			}
+			return customErr
+		}
+	}
+	lastUpdateID, err := b.offsetStore.Load()
+	if err != nil {
+		return custom_error.NewErrorf(err, "Failed to load update offset.")
+	}
+	b.offsets.reset(lastUpdateID)
+	busy := false
+	var backoff time.Duration
+	delay := b.period
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case _ = <-runCtx.Done():
+			timer.Stop()
+			b.logger.Infof("Update-polling exiting")
+			return nil
+		case _ = <-timer.C:
 		}
-		var lastUpdateID int64
-		for {
-			select {
-			case _ = <-b.stopBot:
-				b.logger.Infof("Update-polling goroutine exiting")
-				return
-			default:
-				time.Sleep(b.period)
+		err = nil
+		if !webHookChecked {
+			err = b.checkWebHook(runCtx)
+			webHookChecked = err == nil
+		}
+		if err == nil {
+			busy, err = b.pollIteration(runCtx)
+		}
+		if runCtx.Err() != nil {
+			b.logger.Infof("Update-polling exiting")
+			return nil
+		}
+		if err != nil && IsConflict(err) {
+			if b.onConflict != nil {
+				b.onConflict(err)
+			}
+			switch b.conflictPolicy {
+			case CONFLICT_POLICY_STOP:
+				b.logger.Errorf("Updates are consumed elsewhere, polling stopped. Error: %v.", err)
+				return wrapErrorf(err, "Polling conflict.")
+			case CONFLICT_POLICY_STANDBY:
+				b.logger.Warningf("Updates are consumed elsewhere, standing by. Error: %v.", err)
+				if !b.standBy(runCtx, IsWebHookActiveConflict(err)) {
+					b.logger.Infof("Update-polling exiting")
+					return nil
+				}
+				b.logger.Infof("Standby is over, polling resumed.")
+				webHookChecked = true
+				backoff = 0
+				delay = 0
+				continue
 			}
-			lastUpdateID = b.pollIteration(lastUpdateID)
 		}
-	}()
+		if err != nil {
+			backoff = b.nextBackoff(backoff)
+			b.logger.Errorf("Failed to poll updates, retrying in %v. Error: %v.", backoff, err)
+			delay = backoff
+			continue
+		}
+		backoff = 0
+		delay = b.period
+		if busy {
+			// all received updates are still in progress, there is no point to receive them again right away
+			delay = b.minBackoff
+		}
+	}
 }
 
-func (b *pollingBot) processUpdates(updates *receive.UpdateResultType, lastUpdateID int64) int64 {
+// processUpdates passes new updates to processor. Updates, that are still processed asynchronously, are received again,
+// as offset doesn't pass them, and are skipped. It returns true, if all received updates were skipped.
+func (b *pollingBot) processUpdates(updates *receive.UpdateResultType) bool {
 	if !updates.Ok {
 		b.logger.Errorf("Bad updates object...")
-		return lastUpdateID
-	}
-	if len(updates.Updates) <= 0 {
-		return lastUpdateID
+		return false
 	}
-	lastUpdateIDValue := lastUpdateID
+	busy := len(updates.Updates) > 0
 	for updateIndex := range updates.Updates {
 		lastUpdate := updates.Updates[updateIndex]
 		index := lastUpdate.ID
-		if index > lastUpdateIDValue {
-			lastUpdateIDValue = index
+		if !b.offsets.add(index) {
+			continue
 		}
-		err := b.updateProcessor.Process(&lastUpdate)
+		busy = false
+		err := processSafely(b.logger, b.onPanic, &lastUpdate, func() custom_error.CustomError {
+			return processAsync(b.updateProcessor, &lastUpdate, func(err custom_error.CustomError) {
+				b.offsets.complete(index, err == nil)
+			})
+		})
 		if err != nil {
 			b.logger.Errorf("Error has happened, while processing update id '%d'. Error: %v.", lastUpdate.ID, err)
+			b.offsets.complete(index, false)
 		}
 	}
-	return lastUpdateIDValue
+	return busy
 }
 
-func (b *pollingBot) pollIteration(currentUpdateID int64) (lastUpdateID int64) {
-	lastUpdateID = currentUpdateID
+func (b *pollingBot) pollIteration(ctx context.Context) (busy bool, customErr custom_error.CustomError) {
 	defer func() {
 		r := recover()
 		if r == nil {
@@ -102,33 +298,79 @@ func (b *pollingBot) pollIteration(currentUpdateID int64) (lastUpdateID int64) {
 		} else {
 			b.logger.Errorf("PANIC occured. Recover-objet: %+v. Call-Stack:\n%s.", r, string(debug.Stack()))
 		}
+		customErr = custom_error.MakeErrorf("Panic while polling updates: %v", r)
 	}()
-	getUpdatesRequest, err := b.factory.NewGetUpdates(currentUpdateID+1, 0, 0)
-	if err != nil {
-		b.logger.Errorf("Failed to prepare update request. Error: %v.", err)
-		return
+	getUpdatesRequest, customErr := b.factory.NewGetUpdatesWithAllowed(b.offsets.offset()+1, b.limit, b.timeout, b.allowedUpdates)
+	if customErr != nil {
+		return false, custom_error.NewErrorf(customErr, "Failed to prepare update request.")
 	}
 	for i := range getUpdatesRequest {
-		updates, err := poll(getUpdatesRequest[i])
+		updates, err := poll(ctx, b.sender, getUpdatesRequest[i])
 		if err != nil {
-			b.logger.Errorf("Failed to pull updates. Error: %v.", err)
-			return
+			return false, wrapErrorf(err, "Failed to pull updates.")
 		}
-		lastUpdateID = b.processUpdates(updates, lastUpdateID)
+		busy = b.processUpdates(updates)
 	}
 
-	return
+	return busy, nil
 }
 
 func NewPollingBot(factory *send.RequestFactory, updateProcessor UpdateProcessor, pollPeriod time.Duration, logger logs.Logger) Bot {
-	stopUpdatesChan := make(chan struct{})
-	bot := pollingBot{
-		stopBot:         stopUpdatesChan,
+	return NewPollingBotWithSender(factory, NewHTTPSender(nil), updateProcessor, pollPeriod, logger)
+}
+
+func NewPollingBotWithSender(factory *send.RequestFactory, sender RequestSender, updateProcessor UpdateProcessor, pollPeriod time.Duration, logger logs.Logger) Bot {
+	bot := newPollingBot(factory, sender, updateProcessor, PollingConfig{MinBackoff: pollPeriod}, logger)
+	// short polling: getUpdates returns right away, so period between requests can't be too short
+	bot.timeout = 0
+	bot.period = pollPeriod
+	if bot.period < DEFAULT_POLLING_MIN_PERIOD {
+		bot.period = DEFAULT_POLLING_MIN_PERIOD
+	}
+	return bot
+}
+
+// NewLongPollingBot creates bot, that keeps getUpdates request open for config.Timeout and polls again right after it returns.
+// On errors it backs off exponentially from config.MinBackoff up to config.MaxBackoff.
+func NewLongPollingBot(factory *send.RequestFactory, sender RequestSender, updateProcessor UpdateProcessor, config PollingConfig, logger logs.Logger) Bot {
+	return newPollingBot(factory, sender, updateProcessor, config, logger)
+}
+
+func newPollingBot(factory *send.RequestFactory, sender RequestSender, updateProcessor UpdateProcessor, config PollingConfig, logger logs.Logger) *pollingBot {
+	minBackoff := config.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = DEFAULT_POLLING_MIN_BACKOFF
+	}
+	maxBackoff := config.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DEFAULT_POLLING_MAX_BACKOFF
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+	timeout := config.Timeout
+	if timeout < time.Second {
+		timeout = DEFAULT_POLLING_TIMEOUT
+	}
+	offsetStore := config.OffsetStore
+	if offsetStore == nil {
+		offsetStore = NewMemoryOffsetStore()
+	}
+	return &pollingBot{
 		logger:          logger,
 		factory:         factory,
+		sender:          sender,
+		timeout:         int64(timeout / time.Second),
+		limit:           config.Limit,
+		allowedUpdates:  config.AllowedUpdates,
+		minBackoff:      minBackoff,
+		maxBackoff:      maxBackoff,
+		offsetStore:     offsetStore,
+		offsets:         newOffsetTracker(offsetStore, logger),
+		conflictPolicy:  config.ConflictPolicy,
+		onConflict:      config.OnConflict,
+		resume:          config.Resume,
+		onPanic:         config.OnPanic,
 		updateProcessor: updateProcessor,
-		period:          pollPeriod,
 	}
-	bot.run()
-	return &bot
 }
@@ -0,0 +1,232 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/send"
+)
+
+const (
+	DEFAULT_GLOBAL_PER_SECOND = 30
+	DEFAULT_CHAT_PER_SECOND   = 1
+	DEFAULT_GROUP_PER_MINUTE  = 20
+
+	limiter_cleanup_threshold = 1024
+)
+
+// Limiter throttles outgoing requests. Wait blocks until request to chatID may be sent.
+type Limiter interface {
+	Wait(ctx context.Context, chatID interface{}) custom_error.CustomError
+	QueueLength() int
+}
+
+// RateLimits defines limits for Limiter. Zero values are replaced with Telegram's documented limits.
+type RateLimits struct {
+	GlobalPerSecond float64
+	ChatPerSecond   float64
+	GroupPerMinute  float64
+}
+
+// schedule keeps booked send times in order, so slot can be booked between others and released on cancel.
+type schedule struct {
+	slots []time.Time
+}
+
+// earliest returns the first time not before from, that is at least interval away from every booked slot.
+func (s *schedule) earliest(from time.Time, interval time.Duration) time.Time {
+	candidate := from
+	for _, slot := range s.slots {
+		if !slot.Add(interval).After(candidate) {
+			continue
+		}
+		if !candidate.Add(interval).After(slot) {
+			break
+		}
+		candidate = slot.Add(interval)
+	}
+	return candidate
+}
+
+func (s *schedule) book(slot time.Time) {
+	index := len(s.slots)
+	for index > 0 && s.slots[index-1].After(slot) {
+		index--
+	}
+	s.slots = append(s.slots, time.Time{})
+	copy(s.slots[index+1:], s.slots[index:])
+	s.slots[index] = slot
+}
+
+func (s *schedule) release(slot time.Time) {
+	for i := range s.slots {
+		if s.slots[i].Equal(slot) {
+			s.slots = append(s.slots[:i], s.slots[i+1:]...)
+			return
+		}
+	}
+}
+
+// prune forgets slots, that don't restrict anything after now.
+func (s *schedule) prune(now time.Time, interval time.Duration) {
+	count := 0
+	for count < len(s.slots) && !s.slots[count].Add(interval).After(now) {
+		count++
+	}
+	s.slots = s.slots[count:]
+}
+
+type rateLimiter struct {
+	mutex      sync.Mutex
+	queued     int64
+	global     time.Duration
+	chat       time.Duration
+	group      time.Duration
+	globalPlan schedule
+	chatPlans  map[string]*schedule
+}
+
+func getChatKey(chatID interface{}) (string, bool) {
+	switch v := chatID.(type) {
+	case int64:
+		return fmt.Sprintf("%d", v), v < 0
+	case string:
+		return v, strings.HasPrefix(v, "@") || strings.HasPrefix(v, "-")
+	}
+	return fmt.Sprintf("%v", chatID), false
+}
+
+func (l *rateLimiter) cleanup(now time.Time) {
+	if len(l.chatPlans) < limiter_cleanup_threshold {
+		return
+	}
+	for key, plan := range l.chatPlans {
+		// group interval is the longest one, so slot older than it restricts nothing
+		plan.prune(now, l.group)
+		if len(plan.slots) <= 0 {
+			delete(l.chatPlans, key)
+		}
+	}
+}
+
+// reserve books the earliest slot, that satisfies both chat's and global limits. Both are booked at once,
+// so sends to the same chat keep chat's interval, even if they wait for global slot.
+func (l *rateLimiter) reserve(chatID interface{}) (string, time.Time) {
+	key, isGroup := getChatKey(chatID)
+	interval := l.chat
+	if isGroup {
+		interval = l.group
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	l.cleanup(now)
+	plan, ok := l.chatPlans[key]
+	if !ok {
+		plan = &schedule{}
+		l.chatPlans[key] = plan
+	}
+	plan.prune(now, interval)
+	l.globalPlan.prune(now, l.global)
+	slot := now
+	for {
+		chatSlot := plan.earliest(slot, interval)
+		slot = l.globalPlan.earliest(chatSlot, l.global)
+		if slot.Equal(chatSlot) {
+			break
+		}
+	}
+	plan.book(slot)
+	l.globalPlan.book(slot)
+	return key, slot
+}
+
+// release gives back slot of request, that was cancelled, while waiting for it.
+func (l *rateLimiter) release(key string, slot time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.globalPlan.release(slot)
+	plan, ok := l.chatPlans[key]
+	if ok {
+		plan.release(slot)
+	}
+}
+
+func waitUntil(ctx context.Context, slot time.Time) custom_error.CustomError {
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	select {
+	case _ = <-ctx.Done():
+		timer.Stop()
+		return custom_error.MakeErrorf("Cancelled while waiting for rate limiter. Error: %v", ctx.Err())
+	case _ = <-timer.C:
+		return nil
+	}
+}
+
+// Wait books slot, that satisfies chat's and global limits, and waits for it. Slot is booked between
+// already booked ones, if there is room, so far-off group slots don't hold back other chats.
+func (l *rateLimiter) Wait(ctx context.Context, chatID interface{}) custom_error.CustomError {
+	atomic.AddInt64(&l.queued, 1)
+	defer atomic.AddInt64(&l.queued, -1)
+	key, slot := l.reserve(chatID)
+	customErr := waitUntil(ctx, slot)
+	if customErr != nil {
+		l.release(key, slot)
+		return customErr
+	}
+	return nil
+}
+
+func (l *rateLimiter) QueueLength() int {
+	return int(atomic.LoadInt64(&l.queued))
+}
+
+func intervalFromRate(rate float64, defaultRate float64, period time.Duration) time.Duration {
+	if rate <= 0 {
+		rate = defaultRate
+	}
+	return time.Duration(float64(period) / rate)
+}
+
+// NewRateLimiter creates limiter, that queues requests to keep global, per-chat and per-group rates.
+// Chats with negative IDs and channel usernames are treated as groups.
+func NewRateLimiter(limits RateLimits) Limiter {
+	return &rateLimiter{
+		global:    intervalFromRate(limits.GlobalPerSecond, DEFAULT_GLOBAL_PER_SECOND, time.Second),
+		chat:      intervalFromRate(limits.ChatPerSecond, DEFAULT_CHAT_PER_SECOND, time.Second),
+		group:     intervalFromRate(limits.GroupPerMinute, DEFAULT_GROUP_PER_MINUTE, time.Minute),
+		chatPlans: make(map[string]*schedule),
+	}
+}
+
+type rateLimitedSender struct {
+	sender  RequestSender
+	limiter Limiter
+}
+
+func (s *rateLimitedSender) SendRequest(ctx context.Context, message *send.SendType) ([]byte, custom_error.CustomError) {
+	if message != nil && message.ChatID != nil {
+		customErr := s.limiter.Wait(ctx, message.ChatID)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to wait for rate limiter.")
+		}
+	}
+	return s.sender.SendRequest(ctx, message)
+}
+
+// NewRateLimitedSender creates sender, that passes chat-bound requests through limiter before sending them.
+func NewRateLimitedSender(sender RequestSender, limiter Limiter) RequestSender {
+	return &rateLimitedSender{
+		sender:  sender,
+		limiter: limiter,
+	}
+}
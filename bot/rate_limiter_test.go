@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterKeepsChatIntervalUnderGlobalBacklog(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{GlobalPerSecond: 10, ChatPerSecond: 1}).(*rateLimiter)
+	for i := 0; i < 30; i++ {
+		limiter.reserve(int64(1000 + i))
+	}
+	_, first := limiter.reserve(int64(7))
+	_, second := limiter.reserve(int64(7))
+	if second.Sub(first) < time.Second {
+		t.Fatalf("sends to the same chat are %v apart, expected at least 1s", second.Sub(first))
+	}
+	_, other := limiter.reserve(int64(8))
+	if other.After(second) {
+		t.Fatalf("other chat is held back by chat's interval: %v after second send", other.Sub(second))
+	}
+}
+
+func TestRateLimiterReleasesCancelledSlots(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{GroupPerMinute: 20}).(*rateLimiter)
+	for i := 0; i < 10; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_ = limiter.Wait(ctx, int64(-5))
+		cancel()
+	}
+	start := time.Now()
+	_, slot := limiter.reserve(int64(-5))
+	// one request was sent, the rest were cancelled, so only one group interval (3s) is left to wait
+	if slot.Sub(start) > 4*time.Second {
+		t.Fatalf("cancelled requests kept their slots: next send in %v", slot.Sub(start))
+	}
+	if limiter.QueueLength() != 0 {
+		t.Fatalf("unexpected queue length: %d", limiter.QueueLength())
+	}
+}
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"runtime/debug"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/receive"
+	"github.com/coldze/telebot/send"
+)
+
+// PanicCallback is invoked, when processing of update has panicked. recovered is value, passed to panic.
+type PanicCallback func(update *receive.UpdateType, recovered interface{}, stack []byte)
+
+// processSafely runs process and turns its panic into error, so one update can't break processing of the rest.
+func processSafely(logger logs.Logger, onPanic PanicCallback, update *receive.UpdateType, process func() custom_error.CustomError) (customErr custom_error.CustomError) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		stack := debug.Stack()
+		logger.Errorf("PANIC occured, while processing update id '%d'. Recover-object: %+v. Call-Stack:\n%s.", update.ID, r, string(stack))
+		if onPanic != nil {
+			onPanic(update, r, stack)
+		}
+		customErr = custom_error.MakeErrorf("Panic while processing update id '%d': %v", update.ID, r)
+	}()
+	return process()
+}
+
+type recoveringProcessor struct {
+	logger    logs.Logger
+	processor UpdateProcessor
+	onPanic   PanicCallback
+}
+
+func (p *recoveringProcessor) Process(update *receive.UpdateType) custom_error.CustomError {
+	return processSafely(p.logger, p.onPanic, update, func() custom_error.CustomError {
+		return p.processor.Process(update)
+	})
+}
+
+func (p *recoveringProcessor) ProcessWithReply(update *receive.UpdateType) (*send.SendType, custom_error.CustomError) {
+	var reply *send.SendType
+	customErr := processSafely(p.logger, p.onPanic, update, func() custom_error.CustomError {
+		var err custom_error.CustomError
+		reply, err = processWithReply(p.processor, update)
+		return err
+	})
+	if customErr != nil {
+		return nil, customErr
+	}
+	return reply, nil
+}
+
+func (p *recoveringProcessor) ProcessAsync(update *receive.UpdateType, done UpdateDoneCallback) custom_error.CustomError {
+	return processSafely(p.logger, p.onPanic, update, func() custom_error.CustomError {
+		return processAsync(p.processor, update, done)
+	})
+}
+
+func (p *recoveringProcessor) Wait() {
+	waitProcessed(p.processor)
+}
+
+// NewRecoveringProcessor wraps processor, so panic, while processing update, is logged with call-stack, reported to onPanic
+// (if set) and returned as error.
+func NewRecoveringProcessor(processor UpdateProcessor, onPanic PanicCallback, logger logs.Logger) UpdateProcessor {
+	return &recoveringProcessor{
+		logger:    logger,
+		processor: processor,
+		onPanic:   onPanic,
+	}
+}
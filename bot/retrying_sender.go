@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/send"
+)
+
+type retryingSender struct {
+	sender     RequestSender
+	maxRetries int
+	logger     logs.Logger
+}
+
+func (s *retryingSender) SendRequest(ctx context.Context, message *send.SendType) ([]byte, custom_error.CustomError) {
+	for attempt := 1; ; attempt++ {
+		res, customErr := s.sender.SendRequest(ctx, message)
+		tooManyRequests, ok := customErr.(*TooManyRequestsError)
+		if !ok {
+			return res, customErr
+		}
+		if attempt > s.maxRetries {
+			tooManyRequests.Attempts = attempt
+			return nil, tooManyRequests
+		}
+		s.logger.Warningf("Too many requests. Retrying in %v (attempt %d of %d).", tooManyRequests.RetryAfter, attempt, s.maxRetries)
+		timer := time.NewTimer(tooManyRequests.RetryAfter)
+		select {
+		case _ = <-ctx.Done():
+			timer.Stop()
+			return nil, custom_error.MakeErrorf("Cancelled while waiting to retry. Error: %v", ctx.Err())
+		case _ = <-timer.C:
+		}
+	}
+}
+
+// NewRetryingSender creates sender, that waits for retry_after and repeats requests, rejected with 429, up to maxRetries times.
+// When budget is exhausted, *TooManyRequestsError is returned.
+func NewRetryingSender(sender RequestSender, maxRetries int, logger logs.Logger) RequestSender {
+	return &retryingSender{
+		sender:     sender,
+		maxRetries: maxRetries,
+		logger:     logger,
+	}
+}
@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+// runState tracks single Run invocation of a bot: its cancellation, completion and sends, that are still in flight.
+type runState struct {
+	mutex     sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+	finishing bool
+	sends     int
+	idle      chan struct{}
+}
+
+func (s *runState) start(ctx context.Context) (context.Context, custom_error.CustomError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cancel != nil {
+		return nil, custom_error.MakeErrorf("Bot is already running.")
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	return runCtx, nil
+}
+
+// finish rejects new sends, waits for outstanding ones and marks run as completed.
+func (s *runState) finish() {
+	s.mutex.Lock()
+	s.finishing = true
+	var idle chan struct{}
+	if s.sends > 0 {
+		s.idle = make(chan struct{})
+		idle = s.idle
+	}
+	s.mutex.Unlock()
+	if idle != nil {
+		<-idle
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.finishing = false
+	s.cancel()
+	s.cancel = nil
+	close(s.done)
+}
+
+// stop cancels current run (if any) and waits until it is completed.
+func (s *runState) stop() {
+	s.mutex.Lock()
+	cancel := s.cancel
+	done := s.done
+	s.mutex.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// beginSend registers send, so run is not finished before it. Sends are rejected, while run is finishing.
+func (s *runState) beginSend() custom_error.CustomError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.finishing {
+		return custom_error.MakeErrorf("Bot is shutting down.")
+	}
+	s.sends++
+	return nil
+}
+
+func (s *runState) endSend() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sends--
+	if s.sends <= 0 && s.idle != nil {
+		close(s.idle)
+		s.idle = nil
+	}
+}
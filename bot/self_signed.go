@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+const (
+	SELF_SIGNED_PRIVATE_KEY_FILE = "private.key"
+	SELF_SIGNED_PUBLIC_KEY_FILE  = "public.pem"
+
+	self_signed_key_bits = 2048
+	self_signed_validity = 10 * 365 * 24 * time.Hour
+)
+
+func fileExists(path string) (bool, custom_error.CustomError) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, custom_error.MakeErrorf("Failed to stat '%v'. Error: %v", path, err)
+}
+
+func writePEM(path string, blockType string, data []byte, mode os.FileMode) custom_error.CustomError {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to create '%v'. Error: %v", path, err)
+	}
+	defer file.Close()
+	err = pem.Encode(file, &pem.Block{Type: blockType, Bytes: data})
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to write '%v'. Error: %v", path, err)
+	}
+	return nil
+}
+
+func generateSelfSigned(privateKeyPath string, publicKeyPath string, host string) custom_error.CustomError {
+	key, err := rsa.GenerateKey(rand.Reader, self_signed_key_bits)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to generate private key. Error: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to generate serial number. Error: %v", err)
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: host},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(self_signed_validity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	ip := net.ParseIP(host)
+	if ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to create certificate. Error: %v", err)
+	}
+	customErr := writePEM(privateKeyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), 0600)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to save private key.")
+	}
+	customErr = writePEM(publicKeyPath, "CERTIFICATE", certificate, 0644)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to save certificate.")
+	}
+	return nil
+}
+
+// EnsureSelfSignedCertificate returns paths to key pair in dir. If pair doesn't exist yet, it's generated
+// for host (hostname or IP address).
+func EnsureSelfSignedCertificate(dir string, host string) (string, string, custom_error.CustomError) {
+	if len(host) <= 0 {
+		return "", "", custom_error.MakeErrorf("Host is required to generate certificate.")
+	}
+	privateKeyPath := filepath.Join(dir, SELF_SIGNED_PRIVATE_KEY_FILE)
+	publicKeyPath := filepath.Join(dir, SELF_SIGNED_PUBLIC_KEY_FILE)
+	privateExists, customErr := fileExists(privateKeyPath)
+	if customErr != nil {
+		return "", "", custom_error.NewErrorf(customErr, "Failed to check private key.")
+	}
+	publicExists, customErr := fileExists(publicKeyPath)
+	if customErr != nil {
+		return "", "", custom_error.NewErrorf(customErr, "Failed to check certificate.")
+	}
+	if privateExists && publicExists {
+		return privateKeyPath, publicKeyPath, nil
+	}
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", "", custom_error.MakeErrorf("Failed to create directory '%v'. Error: %v", dir, err)
+	}
+	customErr = generateSelfSigned(privateKeyPath, publicKeyPath, host)
+	if customErr != nil {
+		return "", "", custom_error.NewErrorf(customErr, "Failed to generate self-signed certificate.")
+	}
+	return privateKeyPath, publicKeyPath, nil
+}
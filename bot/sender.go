@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/send"
+)
+
+// RequestSender executes prepared requests against Bot API and returns raw response body.
+// Cancelling ctx aborts the request.
+type RequestSender interface {
+	SendRequest(ctx context.Context, message *send.SendType) ([]byte, custom_error.CustomError)
+}
+
+type httpSender struct {
+	client *http.Client
+}
+
+func (s *httpSender) SendRequest(ctx context.Context, message *send.SendType) ([]byte, custom_error.CustomError) {
+	if message == nil {
+		return nil, custom_error.MakeErrorf("Message is nil. Nothing to send.")
+	}
+
+	var request *http.Request
+	var err error
+	switch message.Type {
+	case send.SEND_TYPE_POST:
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, message.URL, bytes.NewReader(message.Parameters))
+		if err == nil {
+			request.Header.Set("Content-Type", message.ContentType)
+		}
+	case send.SEND_TYPE_GET:
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, message.URL, nil)
+	default:
+		return nil, custom_error.MakeErrorf("Unknown request type: %v", message.Type)
+	}
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to create request. Error: %v", err)
+	}
+	reply, err := s.client.Do(request)
+	if reply != nil {
+		defer reply.Body.Close()
+	}
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to send request. Error: %v", err)
+	}
+	replyBody, err := ioutil.ReadAll(reply.Body)
+	if reply.StatusCode != http.StatusOK {
+		return nil, newResponseError(reply, replyBody)
+	}
+	if err != nil {
+		return replyBody, custom_error.MakeErrorf("Failed to read body. Error: %v", err)
+	}
+	return replyBody, nil
+}
+
+// NewHTTPSender creates sender, that uses provided client (timeouts, proxies, custom transport). If client is nil, http.DefaultClient is used.
+func NewHTTPSender(client *http.Client) RequestSender {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpSender{
+		client: client,
+	}
+}
@@ -4,40 +4,31 @@ import (
 	"github.com/coldze/primitives/custom_error"
 	"github.com/coldze/primitives/logs"
 	"github.com/coldze/telebot/receive"
+	"github.com/coldze/telebot/send"
 )
 
 type SyncUpdateProcessor struct {
 	logger   logs.Logger
+	sender   RequestSender
 	onUpdate UpdateCallback
 }
 
 func (u *SyncUpdateProcessor) Process(update *receive.UpdateType) custom_error.CustomError {
-	response, err := u.onUpdate(update)
-	if err != nil {
-		return custom_error.NewErrorf(err, "Failed to handle update")
-	}
-	if response == nil {
-		return nil //errors.New("Reponse is empty")
-	}
-	responseSentResult, err := sendResponse(response)
-	if err != nil {
-		return custom_error.NewErrorf(err, "Failed to send response")
-	}
-	for i := range responseSentResult {
-		res := responseSentResult[i]
-		if res.Callback != nil {
-			if res.Error != nil {
-				res.Error = custom_error.NewErrorf(res.Error, "Failed to send response")
-			}
-			res.Callback(res.Result, res.Error)
-		}
-	}
-	return nil
+	return handleUpdate(u.onUpdate, u.sender, update)
+}
+
+func (u *SyncUpdateProcessor) ProcessWithReply(update *receive.UpdateType) (*send.SendType, custom_error.CustomError) {
+	return handleUpdateWithReply(u.onUpdate, u.sender, update)
 }
 
 func NewUpdateProcessor(onUpdate UpdateCallback, logger logs.Logger) UpdateProcessor {
+	return NewUpdateProcessorWithSender(onUpdate, NewHTTPSender(nil), logger)
+}
+
+func NewUpdateProcessorWithSender(onUpdate UpdateCallback, sender RequestSender, logger logs.Logger) UpdateProcessor {
 	return &SyncUpdateProcessor{
 		logger:   logger,
+		sender:   sender,
 		onUpdate: onUpdate,
 	}
 }
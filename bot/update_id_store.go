@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+const (
+	DEFAULT_UPDATE_ID_WINDOW = 1000
+)
+
+// UpdateIDStore remembers ids of processed updates. Add has to be atomic: it returns false, if updateID
+// was already remembered. Remove makes store forget update, which processing has failed, so it can be redelivered.
+type UpdateIDStore interface {
+	Add(updateID int64) (bool, custom_error.CustomError)
+	Remove(updateID int64) custom_error.CustomError
+}
+
+// memoryUpdateIDStore keeps last size ids in ring, the oldest one is forgotten first.
+type memoryUpdateIDStore struct {
+	mutex sync.Mutex
+	ids   map[int64]int
+	ring  []int64
+	next  int
+	count int
+}
+
+func (s *memoryUpdateIDStore) Add(updateID int64) (bool, custom_error.CustomError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.ids[updateID]
+	if ok {
+		return false, nil
+	}
+	if s.count >= len(s.ring) {
+		evicted := s.ring[s.next]
+		// id could be removed and added again into another slot
+		slot, ok := s.ids[evicted]
+		if ok && slot == s.next {
+			delete(s.ids, evicted)
+		}
+	} else {
+		s.count++
+	}
+	s.ring[s.next] = updateID
+	s.ids[updateID] = s.next
+	s.next = (s.next + 1) % len(s.ring)
+	return true, nil
+}
+
+func (s *memoryUpdateIDStore) Remove(updateID int64) custom_error.CustomError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// slot in ring is left as is and is reused in its turn
+	delete(s.ids, updateID)
+	return nil
+}
+
+// NewMemoryUpdateIDStore creates store, that remembers last size update ids (DEFAULT_UPDATE_ID_WINDOW, if size isn't positive).
+func NewMemoryUpdateIDStore(size int) UpdateIDStore {
+	if size <= 0 {
+		size = DEFAULT_UPDATE_ID_WINDOW
+	}
+	return &memoryUpdateIDStore{
+		ids:  make(map[int64]int, size),
+		ring: make([]int64, size),
+	}
+}
@@ -1,10 +1,8 @@
 package bot
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"io/ioutil"
-	"net/http"
 
 	"github.com/coldze/primitives/custom_error"
 	"github.com/coldze/telebot/receive"
@@ -32,40 +30,10 @@ func GetMessage(update *receive.UpdateType) *receive.MessageType {
 	return nil
 }
 
-func sendRequest(message *send.SendType) ([]byte, custom_error.CustomError) {
-	if message == nil {
-		return nil, custom_error.MakeErrorf("Message is nil. Nothing to send.")
-	}
-
-	var reply *http.Response
-	var err error
-	switch message.Type {
-	case send.SEND_TYPE_POST:
-		buffer := bytes.NewReader(message.Parameters)
-		reply, err = http.Post(message.URL, message.ContentType, buffer)
-	case send.SEND_TYPE_GET:
-		reply, err = http.Get(message.URL)
-	}
-	if reply != nil {
-		defer reply.Body.Close()
-	}
-	if err != nil {
-		return nil, custom_error.MakeErrorf("Failed to send request. Error: %v", err)
-	}
-	replyBody, err := ioutil.ReadAll(reply.Body)
-	if reply.StatusCode != http.StatusOK {
-		return nil, custom_error.MakeErrorf("Responded bad status: %s. Body: %s", reply.Status, string(replyBody))
-	}
-	if err != nil {
-		return replyBody, custom_error.MakeErrorf("Failed to read body. Error: %v", err)
-	}
-	return replyBody, nil
-}
-
-func poll(message *send.SendType) (*receive.UpdateResultType, custom_error.CustomError) {
-	response, customErr := sendRequest(message)
+func poll(ctx context.Context, sender RequestSender, message *send.SendType) (*receive.UpdateResultType, custom_error.CustomError) {
+	response, customErr := sender.SendRequest(ctx, message)
 	if customErr != nil {
-		return nil, custom_error.NewErrorf(customErr, "Failed to execute poll.")
+		return nil, wrapErrorf(customErr, "Failed to execute poll.")
 	}
 	var updates receive.UpdateResultType
 	err := json.Unmarshal(response, &updates)
@@ -76,18 +44,21 @@ func poll(message *send.SendType) (*receive.UpdateResultType, custom_error.Custo
 }
 
 type hackSendResult struct {
-	Ok          bool        `json:"ok"`
-	ErrorCode   int64       `json:"error_code,omitempty"`
-	Description *string     `json:"description,omitempty"`
-	Result      interface{} `json:"result,omitempty"`
+	Ok          bool                        `json:"ok"`
+	ErrorCode   int64                       `json:"error_code,omitempty"`
+	Description *string                     `json:"description,omitempty"`
+	Parameters  *receive.ResponseParameters `json:"parameters,omitempty"`
+	Result      interface{}                 `json:"result,omitempty"`
 }
 
 func convertHackSendToSendResult(res *hackSendResult) (*receive.SendResult, custom_error.CustomError) {
-	var ok bool
-	_, ok = res.Result.(bool)
+	value, ok := res.Result.(bool)
 	if ok {
 		return &receive.SendResult{
-			Ok: true,
+			Ok:          res.Ok && value,
+			ErrorCode:   res.ErrorCode,
+			Description: res.Description,
+			Parameters:  res.Parameters,
 		}, nil
 	}
 	data, err := json.Marshal(res.Result)
@@ -103,14 +74,15 @@ func convertHackSendToSendResult(res *hackSendResult) (*receive.SendResult, cust
 		Ok:          res.Ok,
 		ErrorCode:   res.ErrorCode,
 		Description: res.Description,
+		Parameters:  res.Parameters,
 		Result:      &msg,
 	}, nil
 }
 
-func sendSingleResponse(message *send.SendType) (*receive.SendResult, custom_error.CustomError) {
-	response, customErr := sendRequest(message)
+func sendSingleResponse(ctx context.Context, sender RequestSender, message *send.SendType) (*receive.SendResult, custom_error.CustomError) {
+	response, customErr := sender.SendRequest(ctx, message)
 	if customErr != nil {
-		return nil, custom_error.NewErrorf(customErr, "Failed to send request.")
+		return nil, wrapErrorf(customErr, "Failed to send request.")
 	}
 	var hackSend hackSendResult
 	err := json.Unmarshal(response, &hackSend)
@@ -127,12 +99,12 @@ func sendSingleResponse(message *send.SendType) (*receive.SendResult, custom_err
 	return nil, custom_error.NewErrorf(customErr, "Failed to convert to send-result")
 }
 
-func sendResponse(messages []*send.SendType) ([]*send.SendResultWithCallback, custom_error.CustomError) {
+func sendResponse(ctx context.Context, sender RequestSender, messages []*send.SendType) ([]*send.SendResultWithCallback, custom_error.CustomError) {
 	results := make([]*send.SendResultWithCallback, 0, len(messages))
 	for i := range messages {
-		res, err := sendSingleResponse(messages[i])
+		res, err := sendSingleResponse(ctx, sender, messages[i])
 		if err != nil {
-			err = custom_error.NewErrorf(err, "Failed to send response")
+			err = wrapErrorf(err, "Failed to send response")
 		}
 		results = append(results, &send.SendResultWithCallback{
 			Result:   res,
@@ -142,3 +114,96 @@ func sendResponse(messages []*send.SendType) ([]*send.SendResultWithCallback, cu
 	}
 	return results, nil
 }
+
+// sendWithCallbacks sends messages, invokes their callbacks and returns first error, that has happened.
+func sendWithCallbacks(ctx context.Context, sender RequestSender, messages []*send.SendType) custom_error.CustomError {
+	responseSentResult, err := sendResponse(ctx, sender, messages)
+	if err != nil {
+		return custom_error.NewErrorf(err, "Failed to send response")
+	}
+	var firstErr custom_error.CustomError
+	for i := range responseSentResult {
+		res := responseSentResult[i]
+		if res.Error != nil {
+			res.Error = wrapErrorf(res.Error, "Failed to send response")
+			if firstErr == nil {
+				firstErr = res.Error
+			}
+		}
+		if res.Callback != nil {
+			res.Callback(res.Result, res.Error)
+		}
+	}
+	return firstErr
+}
+
+// handleUpdateWithReply sends responses, except the last one, if it can be returned in web-hook response body.
+// Only the last one is taken to keep order of responses: the rest are sent before web-hook request is answered.
+func handleUpdateWithReply(onUpdate UpdateCallback, sender RequestSender, update *receive.UpdateType) (*send.SendType, custom_error.CustomError) {
+	response, err := onUpdate(update)
+	if err != nil {
+		return nil, custom_error.NewErrorf(err, "Failed to handle update")
+	}
+	if len(response) <= 0 {
+		return nil, nil
+	}
+	reply := response[len(response)-1]
+	if !reply.CanReplyInline() {
+		reply = nil
+	} else {
+		// reply body is built here, so reply, that can't be written into response body, is sent as usual instead of being lost
+		_, customErr := reply.NewWebHookReply()
+		if customErr != nil {
+			reply = nil
+		} else {
+			response = response[:len(response)-1]
+		}
+	}
+	// send errors are reported through callbacks
+	_ = sendWithCallbacks(context.Background(), sender, response)
+	return reply, nil
+}
+
+func processWithReply(processor UpdateProcessor, update *receive.UpdateType) (*send.SendType, custom_error.CustomError) {
+	replyProcessor, ok := processor.(WebHookReplyProcessor)
+	if ok {
+		return replyProcessor.ProcessWithReply(update)
+	}
+	return nil, processor.Process(update)
+}
+
+// processAsync passes update to asynchronous processor, if it is one, otherwise processes it and invokes done in place.
+// done is not invoked, if error is returned.
+func processAsync(processor UpdateProcessor, update *receive.UpdateType, done UpdateDoneCallback) custom_error.CustomError {
+	asyncProcessor, ok := processor.(AsyncUpdateProcessor)
+	if ok {
+		return asyncProcessor.ProcessAsync(update, done)
+	}
+	customErr := processor.Process(update)
+	if customErr != nil {
+		return customErr
+	}
+	done(nil)
+	return nil
+}
+
+// waitProcessed waits until updates, accepted by drainable processor, are handled.
+func waitProcessed(processor UpdateProcessor) {
+	drainable, ok := processor.(DrainableUpdateProcessor)
+	if ok {
+		drainable.Wait()
+	}
+}
+
+func handleUpdate(onUpdate UpdateCallback, sender RequestSender, update *receive.UpdateType) custom_error.CustomError {
+	response, err := onUpdate(update)
+	if err != nil {
+		return custom_error.NewErrorf(err, "Failed to handle update")
+	}
+	if response == nil {
+		return nil //errors.New("Reponse is empty")
+	}
+	// send errors are reported through callbacks
+	_ = sendWithCallbacks(context.Background(), sender, response)
+	return nil
+}
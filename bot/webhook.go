@@ -1,106 +1,81 @@
 package bot
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-	"net/url"
-	"time"
+	"context"
 
 	"github.com/coldze/primitives/custom_error"
 	"github.com/coldze/primitives/logs"
-	"github.com/coldze/telebot/receive"
 	"github.com/coldze/telebot/send"
 )
 
-type handlingFunction func(w http.ResponseWriter, r *http.Request)
-
-func newHandlingFunc(logger logs.Logger, updateProcessor UpdateProcessor) handlingFunction {
-	return func(w http.ResponseWriter, r *http.Request) {
-		defer r.Body.Close()
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			logger.Errorf("Failed to read update object. Error: %v", custom_error.MakeErrorf("Failed to read request. Error: %v", err))
-			return
-		}
-		var update receive.UpdateType
-		err = json.Unmarshal(body, &update)
-		if err != nil {
-			logger.Errorf("Failed to unmarshal update object. Body: %+v. Error: %v", string(body), custom_error.MakeErrorf("Failed to unmarshal request body. Error: %v", err))
-			return
-		}
-		customErr := updateProcessor.Process(&update)
-		if customErr != nil {
-			logger.Errorf("Error has happened, while processing update id '%d'. Error: %v.", update.ID, custom_error.NewErrorf(customErr, "Failed to process update."))
-		}
-	}
+// WebHookConfig configures web-hook bot. URL is registered with setWebhook and its path is served.
+// Listener is configured with WebHookListenerConfig, incoming requests are authenticated according to WebHookHandlerConfig.
+type WebHookConfig struct {
+	WebHookHandlerConfig
+	WebHookListenerConfig
+	URL string
 }
 
+// webHookBot is single bot, served by its own WebHookServer.
 type webHookBot struct {
-	logger          logs.Logger
-	factory         *send.RequestFactory
-	updateProcessor UpdateProcessor
+	server *WebHookServer
+	sender RequestSender
 }
 
 func (b *webHookBot) Stop() {
+	b.server.Stop()
 }
 
-func (b *webHookBot) Send([]*send.SendType) custom_error.CustomError {
-	return custom_error.MakeErrorf("Not implemented.")
-}
-
-func pingHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Body != nil {
-		defer r.Body.Close()
+func (b *webHookBot) Send(msg []*send.SendType) custom_error.CustomError {
+	err := b.server.state.beginSend()
+	if err != nil {
+		return custom_error.NewErrorf(err, "Failed to send")
 	}
-	w.Write([]byte(fmt.Sprintf("Ping from '%v'.\nReceived at: %v.", r.RemoteAddr, time.Now().UTC())))
-}
-
-func (b *webHookBot) singUp(listenUrl string, port int64, sslPrivateKey string, sslPublicKey string, isSelfSigned bool, handlingFunction handlingFunction) custom_error.CustomError {
-	u, err := url.Parse(listenUrl)
+	defer b.server.state.endSend()
+	err = sendWithCallbacks(context.Background(), b.sender, msg)
 	if err != nil {
-		return custom_error.MakeErrorf("Failed to parse url '%v'. Error: %v", listenUrl, err)
+		return wrapErrorf(err, "Failed to send")
 	}
+	return nil
+}
 
-	http.HandleFunc(u.Path, handlingFunction)
-	sslSubscribeKey := ""
-	if isSelfSigned {
-		sslSubscribeKey = sslPublicKey
-	}
-	signUp, customErr := b.factory.NewSubscribe(listenUrl, sslSubscribeKey)
+func (b *webHookBot) Run(ctx context.Context) custom_error.CustomError {
+	customErr := b.server.Run(ctx)
 	if customErr != nil {
-		return custom_error.NewErrorf(customErr, "Failed to subscribe.")
+		return wrapErrorf(customErr, "Web-hook bot failed.")
 	}
+	return nil
+}
 
-	signUpResult := []byte{}
-	for i := range signUp {
-		res, customErr := sendRequest(signUp[i])
-		if customErr != nil {
-			return custom_error.NewErrorf(customErr, "Failed to send request.")
-		}
-		signUpResult = append(signUpResult, res...)
-	}
+// NewWebHookBot creates web-hook bot, that serves TLS with given key pair. Plain HTTP is served only,
+// if it's enabled explicitly with WebHookListenerConfig.PlainHTTP (see NewWebHookBotWithConfig).
+func NewWebHookBot(factory *send.RequestFactory, updateProcessor UpdateProcessor, url string, listenPort int64, sslPrivateKey string, sslPublicKey string, isSelfSigned bool, logger logs.Logger) (Bot, custom_error.CustomError) {
+	return NewWebHookBotWithSender(factory, NewHTTPSender(nil), updateProcessor, url, listenPort, sslPrivateKey, sslPublicKey, isSelfSigned, logger)
+}
 
-	http.HandleFunc("/ping", pingHandler)
-	b.logger.Infof("Web-hook sign-up result: %s", string(signUpResult))
-	err = http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-	if err == nil {
-		return nil
-	}
-	return custom_error.MakeErrorf("Failed to start http server. Error: %v", err)
-	//return http.ListenAndServeTLS(fmt.Sprintf(":%d", port), sslPublicKey, sslPrivateKey, nil)
+func NewWebHookBotWithSender(factory *send.RequestFactory, sender RequestSender, updateProcessor UpdateProcessor, listenUrl string, listenPort int64, sslPrivateKey string, sslPublicKey string, isSelfSigned bool, logger logs.Logger) (Bot, custom_error.CustomError) {
+	return NewWebHookBotWithConfig(factory, sender, updateProcessor, WebHookConfig{
+		URL: listenUrl,
+		WebHookListenerConfig: WebHookListenerConfig{
+			ListenPort:   listenPort,
+			PrivateKey:   sslPrivateKey,
+			PublicKey:    sslPublicKey,
+			IsSelfSigned: isSelfSigned,
+		},
+	}, logger)
 }
 
-func NewWebHookBot(factory *send.RequestFactory, updateProcessor UpdateProcessor, url string, listenPort int64, sslPrivateKey string, sslPublicKey string, isSelfSigned bool, logger logs.Logger) (Bot, custom_error.CustomError) {
-	bot := webHookBot{
-		logger:  logger,
-		factory: factory,
+func NewWebHookBotWithConfig(factory *send.RequestFactory, sender RequestSender, updateProcessor UpdateProcessor, config WebHookConfig, logger logs.Logger) (Bot, custom_error.CustomError) {
+	server, customErr := NewWebHookServer(config.WebHookListenerConfig, logger)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to create web-hook server.")
 	}
-	handlingFunction := newHandlingFunc(logger, updateProcessor)
-	err := bot.singUp(url, listenPort, sslPrivateKey, sslPublicKey, isSelfSigned, handlingFunction)
-	if err != nil {
-		return nil, custom_error.NewErrorf(err, "Failed to sign-up for changes")
+	customErr = server.AddBot(factory, sender, updateProcessor, config.URL, config.WebHookHandlerConfig)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to add bot to web-hook server.")
 	}
-	return &bot, nil
+	return &webHookBot{
+		server: server,
+		sender: sender,
+	}, nil
 }
@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+const (
+	SECRET_TOKEN_HEADER = "X-Telegram-Bot-Api-Secret-Token"
+
+	forwarded_for_header = "X-Forwarded-For"
+	secret_token_max_len = 256
+)
+
+// TELEGRAM_NETWORKS are ranges, web-hook requests are sent from (https://core.telegram.org/bots/webhooks).
+var TELEGRAM_NETWORKS = []string{"149.154.160.0/20", "91.108.4.0/22"}
+
+type webHookAuth struct {
+	secretToken       []byte
+	allowedNetworks   []*net.IPNet
+	trustForwardedFor bool
+}
+
+func isValidSecretToken(token string) bool {
+	if len(token) > secret_token_max_len {
+		return false
+	}
+	for _, c := range token {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func (a *webHookAuth) getRemoteIP(r *http.Request) net.IP {
+	if a.trustForwardedFor {
+		forwarded := r.Header.Get(forwarded_for_header)
+		if len(forwarded) > 0 {
+			// the last address is the one, that was added by our proxy
+			hops := strings.Split(forwarded, ",")
+			return net.ParseIP(strings.TrimSpace(hops[len(hops)-1]))
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+func (a *webHookAuth) isAllowedIP(r *http.Request) bool {
+	if len(a.allowedNetworks) <= 0 {
+		return true
+	}
+	ip := a.getRemoteIP(r)
+	if ip == nil {
+		return false
+	}
+	for i := range a.allowedNetworks {
+		if a.allowedNetworks[i].Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *webHookAuth) hasValidToken(r *http.Request) bool {
+	if len(a.secretToken) <= 0 {
+		return true
+	}
+	token := []byte(r.Header.Get(SECRET_TOKEN_HEADER))
+	return subtle.ConstantTimeCompare(token, a.secretToken) == 1
+}
+
+// check returns http status to reject request with, or http.StatusOK, if request is authentic.
+func (a *webHookAuth) check(r *http.Request) int {
+	if !a.isAllowedIP(r) {
+		return http.StatusForbidden
+	}
+	if !a.hasValidToken(r) {
+		return http.StatusUnauthorized
+	}
+	return http.StatusOK
+}
+
+func newWebHookAuth(secretToken string, allowedNetworks []string, trustForwardedFor bool) (*webHookAuth, custom_error.CustomError) {
+	if !isValidSecretToken(secretToken) {
+		return nil, custom_error.MakeErrorf("Invalid secret token. Expected up to %d characters A-Z, a-z, 0-9, _ and -.", secret_token_max_len)
+	}
+	auth := &webHookAuth{
+		secretToken:       []byte(secretToken),
+		allowedNetworks:   make([]*net.IPNet, 0, len(allowedNetworks)),
+		trustForwardedFor: trustForwardedFor,
+	}
+	for i := range allowedNetworks {
+		_, network, err := net.ParseCIDR(allowedNetworks[i])
+		if err != nil {
+			return nil, custom_error.MakeErrorf("Failed to parse network '%v'. Error: %v", allowedNetworks[i], err)
+		}
+		auth.allowedNetworks = append(auth.allowedNetworks, network)
+	}
+	return auth, nil
+}
@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/receive"
+	"github.com/coldze/telebot/send"
+)
+
+const (
+	WEBHOOK_FAILURE_DROP = iota + 1
+	WEBHOOK_FAILURE_REDELIVER
+)
+
+// WebHookHandlerConfig configures authentication of web-hook requests.
+// If SecretToken is set, requests without matching X-Telegram-Bot-Api-Secret-Token header are rejected with 401.
+// If AllowedNetworks are set (e.g. TELEGRAM_NETWORKS), requests from other addresses are rejected with 403;
+// TrustForwardedFor makes handler take address from X-Forwarded-For, set by reverse proxy.
+// ReplyInline makes handler answer with one of update's responses in response body, saving a request,
+// if UpdateProcessor supports it (see WebHookReplyProcessor). Telegram doesn't report result of such response.
+// Panics of processor are recovered and reported to OnPanic. FailurePolicy defines response, when processing fails:
+// WEBHOOK_FAILURE_DROP (default) answers 200, so update is dropped, WEBHOOK_FAILURE_REDELIVER answers 500,
+// so Telegram delivers update again.
+type WebHookHandlerConfig struct {
+	SecretToken       string
+	AllowedNetworks   []string
+	TrustForwardedFor bool
+	ReplyInline       bool
+	OnPanic           PanicCallback
+	FailurePolicy     int64
+}
+
+// WebHookHandler is http.Handler, that accepts updates, pushed by Telegram, and passes them to UpdateProcessor.
+// It can be mounted on any router; web-hook itself is registered separately with RegisterWebHook.
+type WebHookHandler struct {
+	logger          logs.Logger
+	updateProcessor UpdateProcessor
+	auth            *webHookAuth
+	replyInline     bool
+	onPanic         PanicCallback
+	failurePolicy   int64
+}
+
+func (h *WebHookHandler) process(update *receive.UpdateType) (*send.SendType, custom_error.CustomError) {
+	var reply *send.SendType
+	customErr := processSafely(h.logger, h.onPanic, update, func() custom_error.CustomError {
+		if !h.replyInline {
+			return h.updateProcessor.Process(update)
+		}
+		var err custom_error.CustomError
+		reply, err = processWithReply(h.updateProcessor, update)
+		return err
+	})
+	if customErr != nil {
+		return nil, customErr
+	}
+	return reply, nil
+}
+
+func (h *WebHookHandler) writeReply(w http.ResponseWriter, reply *send.SendType) {
+	body, customErr := reply.NewWebHookReply()
+	if customErr != nil {
+		h.logger.Errorf("Failed to create web-hook reply. Error: %v", customErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err := w.Write(body)
+	if err != nil {
+		h.logger.Errorf("Failed to write web-hook reply. Error: %v", err)
+	}
+}
+
+func (h *WebHookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	status := h.auth.check(r)
+	if status != http.StatusOK {
+		h.logger.Warningf("Rejected web-hook request from '%v' with status %d.", r.RemoteAddr, status)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Errorf("Failed to read update object. Error: %v", custom_error.MakeErrorf("Failed to read request. Error: %v", err))
+		return
+	}
+	var update receive.UpdateType
+	err = json.Unmarshal(body, &update)
+	if err != nil {
+		h.logger.Errorf("Failed to unmarshal update object. Body: %+v. Error: %v", string(body), custom_error.MakeErrorf("Failed to unmarshal request body. Error: %v", err))
+		return
+	}
+	reply, customErr := h.process(&update)
+	if customErr != nil {
+		h.logger.Errorf("Error has happened, while processing update id '%d'. Error: %v.", update.ID, custom_error.NewErrorf(customErr, "Failed to process update."))
+		if h.failurePolicy == WEBHOOK_FAILURE_REDELIVER {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+	if reply != nil {
+		h.writeReply(w, reply)
+	}
+}
+
+func NewWebHookHandler(updateProcessor UpdateProcessor, config WebHookHandlerConfig, logger logs.Logger) (*WebHookHandler, custom_error.CustomError) {
+	auth, customErr := newWebHookAuth(config.SecretToken, config.AllowedNetworks, config.TrustForwardedFor)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to configure web-hook authentication.")
+	}
+	return &WebHookHandler{
+		logger:          logger,
+		updateProcessor: updateProcessor,
+		auth:            auth,
+		replyInline:     config.ReplyInline,
+		onPanic:         config.OnPanic,
+		failurePolicy:   config.FailurePolicy,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/receive"
+	"github.com/coldze/telebot/send"
+	"github.com/coldze/telebot/send/requests"
+)
+
+func sendAll(ctx context.Context, sender RequestSender, messages []*send.SendType) custom_error.CustomError {
+	for i := range messages {
+		_, customErr := sendSingleResponse(ctx, sender, messages[i])
+		if customErr != nil {
+			return wrapErrorf(customErr, "Failed to send request.")
+		}
+	}
+	return nil
+}
+
+// RegisterWebHook makes Telegram push updates to url. sslPublicKey (path to certificate) is uploaded, if set,
+// which is required for self-signed certificates.
+func RegisterWebHook(ctx context.Context, factory *send.RequestFactory, sender RequestSender, url string, sslPublicKey string, secretToken string) custom_error.CustomError {
+	return SetWebHook(ctx, factory, sender, &requests.SetWebhook{
+		URL:         url,
+		Certificate: sslPublicKey,
+		SecretToken: secretToken,
+	})
+}
+
+// SetWebHook registers web-hook with all options of setWebhook (max_connections, allowed_updates, ip_address etc).
+func SetWebHook(ctx context.Context, factory *send.RequestFactory, sender RequestSender, r *requests.SetWebhook) custom_error.CustomError {
+	signUp, customErr := factory.NewSetWebhook(r)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to create subscribe request.")
+	}
+	customErr = sendAll(ctx, sender, signUp)
+	if customErr != nil {
+		return wrapErrorf(customErr, "Failed to subscribe.")
+	}
+	return nil
+}
+
+// DeleteWebHook removes web-hook, so updates can be received with getUpdates again.
+func DeleteWebHook(ctx context.Context, factory *send.RequestFactory, sender RequestSender, dropPendingUpdates bool) custom_error.CustomError {
+	request, customErr := factory.NewDeleteWebhook(dropPendingUpdates)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to create delete webhook request.")
+	}
+	customErr = sendAll(ctx, sender, request)
+	if customErr != nil {
+		return wrapErrorf(customErr, "Failed to delete web-hook.")
+	}
+	return nil
+}
+
+// GetWebHookInfo returns current web-hook status: url, pending update count, last delivery error etc.
+// URL is empty, if web-hook is not set.
+func GetWebHookInfo(ctx context.Context, factory *send.RequestFactory, sender RequestSender) (*receive.WebhookInfo, custom_error.CustomError) {
+	request, customErr := factory.NewGetWebhookInfo()
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to create get webhook info request.")
+	}
+	if len(request) != 1 {
+		return nil, custom_error.MakeErrorf("Unexpected get webhook info request count: %d", len(request))
+	}
+	response, customErr := sender.SendRequest(ctx, request[0])
+	if customErr != nil {
+		return nil, wrapErrorf(customErr, "Failed to get web-hook info.")
+	}
+	var info receive.WebhookInfoResult
+	err := json.Unmarshal(response, &info)
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to unmarshal web-hook info. Error: %v", err)
+	}
+	if !info.Ok || info.Result == nil {
+		return nil, custom_error.MakeErrorf("Failed to get web-hook info. Response: %v", string(response))
+	}
+	return info.Result, nil
+}
@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/primitives/logs"
+	"github.com/coldze/telebot/send"
+)
+
+const (
+	DEFAULT_SHUTDOWN_TIMEOUT = 30 * time.Second
+)
+
+// WebHookListenerConfig configures http server, that receives web-hook requests.
+// Server uses TLSConfig, if it is set, otherwise PublicKey/PrivateKey files, which are reloaded, when rotated.
+// PlainHTTP disables TLS, e.g. when server is behind TLS-terminating reverse proxy.
+// IsSelfSigned makes bots upload PublicKey to Telegram during registration.
+// If neither keys nor TLSConfig are set, but CertificateDir is, self-signed pair for host of the first bot's URL
+// is generated there (or reused, if it was generated before).
+type WebHookListenerConfig struct {
+	ListenPort     int64
+	PrivateKey     string
+	PublicKey      string
+	IsSelfSigned   bool
+	TLSConfig      *tls.Config
+	PlainHTTP      bool
+	CertificateDir string
+}
+
+type webHookEndpoint struct {
+	url             string
+	factory         *send.RequestFactory
+	sender          RequestSender
+	updateProcessor UpdateProcessor
+	secretToken     string
+}
+
+// WebHookServer serves web-hooks of several bots on one listener. Each bot is routed by path of its URL.
+type WebHookServer struct {
+	state     runState
+	logger    logs.Logger
+	config    WebHookListenerConfig
+	mutex     sync.Mutex
+	mux       *http.ServeMux
+	endpoints map[string]*webHookEndpoint
+}
+
+func pingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+	w.Write([]byte(fmt.Sprintf("Ping from '%v'.\nReceived at: %v.", r.RemoteAddr, time.Now().UTC())))
+}
+
+func (s *WebHookServer) prepareCertificate(host string) custom_error.CustomError {
+	if s.config.PlainHTTP || s.config.TLSConfig != nil || len(s.config.PrivateKey) > 0 || len(s.config.PublicKey) > 0 {
+		return nil
+	}
+	privateKey, publicKey, customErr := EnsureSelfSignedCertificate(s.config.CertificateDir, host)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to prepare self-signed certificate.")
+	}
+	s.config.PrivateKey = privateKey
+	s.config.PublicKey = publicKey
+	s.config.IsSelfSigned = true
+	s.logger.Infof("Using self-signed certificate '%v' for host '%v'.", publicKey, host)
+	return nil
+}
+
+// AddBot routes requests to path of listenUrl to updateProcessor. Web-hook is registered, when server is started,
+// so bots should be added before Run.
+func (s *WebHookServer) AddBot(factory *send.RequestFactory, sender RequestSender, updateProcessor UpdateProcessor, listenUrl string, config WebHookHandlerConfig) custom_error.CustomError {
+	u, err := url.Parse(listenUrl)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to parse url '%v'. Error: %v", listenUrl, err)
+	}
+	path := u.Path
+	if len(path) <= 0 {
+		path = "/"
+	}
+	handler, customErr := NewWebHookHandler(updateProcessor, config, s.logger)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to create web-hook handler.")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.endpoints[path]
+	if ok {
+		return custom_error.MakeErrorf("Path '%v' is already served.", path)
+	}
+	customErr = s.prepareCertificate(u.Hostname())
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to prepare certificate.")
+	}
+	s.endpoints[path] = &webHookEndpoint{
+		url:             listenUrl,
+		factory:         factory,
+		sender:          sender,
+		updateProcessor: updateProcessor,
+		secretToken:     config.SecretToken,
+	}
+	s.mux.Handle(path, handler)
+	return nil
+}
+
+func (s *WebHookServer) signUp(ctx context.Context) custom_error.CustomError {
+	sslSubscribeKey := ""
+	if s.config.IsSelfSigned {
+		sslSubscribeKey = s.config.PublicKey
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, endpoint := range s.endpoints {
+		customErr := RegisterWebHook(ctx, endpoint.factory, endpoint.sender, endpoint.url, sslSubscribeKey, endpoint.secretToken)
+		if customErr != nil {
+			return wrapErrorf(customErr, "Failed to register web-hook '%v'.", endpoint.url)
+		}
+		s.logger.Infof("Web-hook registered: %v", endpoint.url)
+	}
+	return nil
+}
+
+func (s *WebHookServer) getTLSConfig() (*tls.Config, custom_error.CustomError) {
+	if s.config.TLSConfig != nil {
+		return s.config.TLSConfig, nil
+	}
+	reloader, customErr := newCertificateReloader(s.config.PublicKey, s.config.PrivateKey)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to create certificate reloader.")
+	}
+	return &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+	}, nil
+}
+
+// waitProcessed waits until updates, queued by asynchronous processors of bots, are handled.
+func (s *WebHookServer) waitProcessed() {
+	s.mutex.Lock()
+	processors := make([]UpdateProcessor, 0, len(s.endpoints))
+	for _, endpoint := range s.endpoints {
+		processors = append(processors, endpoint.updateProcessor)
+	}
+	s.mutex.Unlock()
+	for _, processor := range processors {
+		waitProcessed(processor)
+	}
+}
+
+// Run binds listener, registers web-hooks of all bots and serves them until ctx is cancelled or Stop is called.
+func (s *WebHookServer) Run(ctx context.Context) custom_error.CustomError {
+	runCtx, customErr := s.state.start(ctx)
+	if customErr != nil {
+		return custom_error.NewErrorf(customErr, "Failed to start web-hook server.")
+	}
+	defer s.state.finish()
+	defer s.waitProcessed()
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.config.ListenPort),
+		Handler: s.mux,
+	}
+	if !s.config.PlainHTTP {
+		server.TLSConfig, customErr = s.getTLSConfig()
+		if customErr != nil {
+			return custom_error.NewErrorf(customErr, "Failed to configure TLS.")
+		}
+	}
+
+	// listener is bound before web-hooks are registered, so they don't point to endpoint, that can't be served
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to listen on '%v'. Error: %v", server.Addr, err)
+	}
+	customErr = s.signUp(runCtx)
+	if customErr != nil {
+		listener.Close()
+		return wrapErrorf(customErr, "Failed to sign-up for changes")
+	}
+
+	serveResult := make(chan error, 1)
+	go func() {
+		if s.config.PlainHTTP {
+			serveResult <- server.Serve(listener)
+			return
+		}
+		serveResult <- server.ServeTLS(listener, "", "")
+	}()
+	select {
+	case err := <-serveResult:
+		return custom_error.MakeErrorf("Failed to start http server. Error: %v", err)
+	case _ = <-runCtx.Done():
+	}
+
+	s.logger.Infof("Web-hook server shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to shutdown http server. Error: %v", err)
+	}
+	return nil
+}
+
+func (s *WebHookServer) Stop() {
+	s.state.stop()
+}
+
+func NewWebHookServer(config WebHookListenerConfig, logger logs.Logger) (*WebHookServer, custom_error.CustomError) {
+	hasKeys := len(config.PrivateKey) > 0 && len(config.PublicKey) > 0
+	noKeys := len(config.PrivateKey) <= 0 && len(config.PublicKey) <= 0
+	if !config.PlainHTTP && config.TLSConfig == nil && !hasKeys && !(noKeys && len(config.CertificateDir) > 0) {
+		return nil, custom_error.MakeErrorf("Key pair, TLS config or certificate directory is required, unless plain HTTP is enabled.")
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", pingHandler)
+	return &WebHookServer{
+		logger:    logger,
+		config:    config,
+		mux:       mux,
+		endpoints: make(map[string]*webHookEndpoint),
+	}, nil
+}
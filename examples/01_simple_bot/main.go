@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -96,6 +97,12 @@ func main() {
 			return
 		}
 	}()
+	go func() {
+		runErr := botApp.Run(context.Background())
+		if runErr != nil {
+			logger.Errorf("Bot stopped with error. Error: %v.", runErr)
+		}
+	}()
 	defer botApp.Stop()
 	logger.Infof("Bot started. Press Enter to stop.")
 	_, _ = fmt.Scanf("\n")
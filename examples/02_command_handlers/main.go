@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
@@ -187,8 +189,19 @@ func main() {
 		return
 	}
 
-	updateProcessor := bot.NewUpdateProcessor(onUpdate, logger)
-	botApp := bot.NewPollingBot(requestFactory, updateProcessor, time.Second, logger)
+	limiter := bot.NewRateLimiter(bot.RateLimits{})
+	sender := bot.NewRateLimitedSender(bot.NewRetryingSender(bot.NewHTTPSender(&http.Client{Timeout: time.Minute}), 3, logger), limiter)
+	updateProcessor := bot.NewUpdateProcessorWithSender(onUpdate, sender, logger)
+	botApp := bot.NewLongPollingBot(requestFactory, sender, updateProcessor, bot.PollingConfig{
+		Timeout:        30 * time.Second,
+		AllowedUpdates: []string{receive.UPDATE_TYPE_MESSAGE, receive.UPDATE_TYPE_CALLBACK_QUERY},
+	}, logger)
+	go func() {
+		runErr := botApp.Run(context.Background())
+		if runErr != nil {
+			logger.Errorf("Bot stopped with error. Error: %v.", runErr)
+		}
+	}()
 	defer botApp.Stop()
 	logger.Infof("Bot started. Press Enter to stop.")
 	_, _ = fmt.Scanf("\n")
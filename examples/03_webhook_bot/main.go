@@ -2,9 +2,12 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/coldze/primitives/custom_error"
 	"github.com/coldze/primitives/logs"
@@ -20,6 +23,7 @@ const (
 	BOT_SSL_PUBLIC_KEY          = "BOT_SSL_PUBLIC"
 	BOT_SSL_PRIVATE_KEY         = "BOT_SSL_PRIVATE"
 	BOT_SSL_SELF_SIGNED         = "BOT_SSL_SELF_SIGNED"
+	BOT_SSL_CERT_DIR            = "BOT_SSL_CERT_DIR"
 	BOT_UPDATE_CALLBACK_URL_KEY = "BOT_UPDATE_CALLBACK_URL"
 	BOT_HTTPS_LISTEN_PORT_KEY   = "BOT_HTTPS_LISTEN_PORT"
 
@@ -182,15 +186,12 @@ func main() {
 		return
 	}
 
-	sslPublic, ok := os.LookupEnv(BOT_SSL_PUBLIC_KEY)
-	if !ok {
-		logger.Errorf("Failed to get ssl public key. Expected to have environment variable '%s'.", BOT_SSL_PUBLIC_KEY)
-		return
-	}
-
-	sslPrivate, ok := os.LookupEnv(BOT_SSL_PRIVATE_KEY)
-	if !ok {
-		logger.Errorf("Failed to get ssl private key. Expected to have environment variable '%s'.", BOT_SSL_PRIVATE_KEY)
+	// if key pair is not provided, self-signed one is generated in BOT_SSL_CERT_DIR
+	sslPublic, _ := os.LookupEnv(BOT_SSL_PUBLIC_KEY)
+	sslPrivate, _ := os.LookupEnv(BOT_SSL_PRIVATE_KEY)
+	sslCertDir, _ := os.LookupEnv(BOT_SSL_CERT_DIR)
+	if (len(sslPublic) <= 0 || len(sslPrivate) <= 0) && len(sslCertDir) <= 0 {
+		logger.Errorf("Failed to get ssl keys. Expected to have environment variables '%s' and '%s' or '%s'.", BOT_SSL_PUBLIC_KEY, BOT_SSL_PRIVATE_KEY, BOT_SSL_CERT_DIR)
 		return
 	}
 
@@ -292,9 +293,30 @@ func main() {
 	}
 
 	updateProcessor := bot.NewUpdateProcessor(onUpdate, logger)
-	_, err = bot.NewWebHookBot(requestFactory, updateProcessor, updateCallbackURL, listenPort, sslPrivate, sslPublic, isSelfSigned, logger)
+	botApp, err := bot.NewWebHookBotWithConfig(requestFactory, bot.NewHTTPSender(nil), updateProcessor, bot.WebHookConfig{
+		URL: updateCallbackURL,
+		WebHookListenerConfig: bot.WebHookListenerConfig{
+			ListenPort:     listenPort,
+			PrivateKey:     sslPrivate,
+			PublicKey:      sslPublic,
+			IsSelfSigned:   isSelfSigned,
+			CertificateDir: sslCertDir,
+		},
+	}, logger)
+	if err != nil {
+		logger.Errorf("Failed to create bot. Error: %v.", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+	}()
+	err = botApp.Run(ctx)
 	if err != nil {
-		logger.Errorf("Failed to start bot. Error: %v.", err)
+		logger.Errorf("Bot stopped with error. Error: %v.", err)
 		return
 	}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -120,6 +121,53 @@ func NewOnListCommand(users *UsersMemory, requestFactory *send.RequestFactory, l
 	}, nil
 }
 
+func newInlineKeyboard() markup.InlineKeyboardMarkupType {
+	var inlineKeyboardMarkup markup.InlineKeyboardMarkupType
+	inlineKeyboardMarkup.Buttons = make([][]markup.InlineKeyboardButtonType, 2)
+	inlineKeyboardMarkup.Buttons[0] = make([]markup.InlineKeyboardButtonType, 2)
+	inlineKeyboardMarkup.Buttons[0][0].Text = "Open google"
+	inlineKeyboardMarkup.Buttons[0][0].URL = "https://www.google.com"
+	inlineKeyboardMarkup.Buttons[0][1].Text = "Open bot API manual"
+	inlineKeyboardMarkup.Buttons[0][1].URL = "https://core.telegram.org/bots/api"
+	inlineKeyboardMarkup.Buttons[1] = make([]markup.InlineKeyboardButtonType, 1)
+	inlineKeyboardMarkup.Buttons[1][0].Text = "What time is it?"
+	inlineKeyboardMarkup.Buttons[1][0].CallbackData = "/time"
+	return inlineKeyboardMarkup
+}
+
+// NewOnTimeCallback handles button of inline keyboard: message with keyboard is edited in place.
+func NewOnTimeCallback(requestFactory *send.RequestFactory, logger logs.Logger) (bot.CommandHandler, custom_error.CustomError) {
+	if requestFactory == nil {
+		return nil, nil
+	}
+	return func(command *bot.CommandCallType) ([]*send.SendType, custom_error.CustomError) {
+		logger.Infof("Time callback handler invoked.")
+		callbackQuery := command.MetaInfo.CallbackQuery
+		if callbackQuery == nil || callbackQuery.Message == nil || callbackQuery.Message.Chat == nil {
+			return nil, custom_error.MakeErrorf("MESSAGE missing")
+		}
+		editMessage := &requests.EditMessageText{
+			MessageTarget: requests.MessageTarget{
+				ChatID:    callbackQuery.Message.Chat.ID,
+				MessageID: callbackQuery.Message.ID,
+			},
+			Text:        fmt.Sprintf("Choose:\nIt's %v.", time.Now().Format(time.Kitchen)),
+			ReplyMarkup: newInlineKeyboard(),
+		}
+		res, err := requestFactory.NewEditMessageText(editMessage, nil)
+		if err != nil {
+			return nil, custom_error.NewErrorf(err, "Failed to create edit message text.")
+		}
+		answer, err := requestFactory.NewAnswerCallbackQuery(&requests.AnswerCallbackQuery{
+			CallbackQueryID: callbackQuery.ID,
+		})
+		if err != nil {
+			return nil, custom_error.NewErrorf(err, "Failed to create answer callback query.")
+		}
+		return append(res, answer...), nil
+	}, nil
+}
+
 func NewOnInlineCommand(users *UsersMemory, requestFactory *send.RequestFactory, logger logs.Logger) (bot.CommandHandler, custom_error.CustomError) {
 	if users == nil {
 		return nil, nil
@@ -136,17 +184,10 @@ func NewOnInlineCommand(users *UsersMemory, requestFactory *send.RequestFactory,
 			return nil, custom_error.MakeErrorf("CHAT missing")
 		}
 
-		var inlineKeyboardMarkup markup.InlineKeyboardMarkupType
-		inlineKeyboardMarkup.Buttons = make([][]markup.InlineKeyboardButtonType, 1)
-		inlineKeyboardMarkup.Buttons[0] = make([]markup.InlineKeyboardButtonType, 2)
-		inlineKeyboardMarkup.Buttons[0][0].Text = "Open google"
-		inlineKeyboardMarkup.Buttons[0][0].URL = "https://www.google.com"
-		inlineKeyboardMarkup.Buttons[0][1].Text = "Open bot API manual"
-		inlineKeyboardMarkup.Buttons[0][1].URL = "https://core.telegram.org/bots/api"
 		sendMessage := &requests.SendMessage{
 			Base: requests.Base{
 				ChatID:      command.MetaInfo.Message.Chat.ID,
-				ReplyMarkup: inlineKeyboardMarkup,
+				ReplyMarkup: newInlineKeyboard(),
 			},
 			Text: "Choose:",
 		}
@@ -229,6 +270,16 @@ func main() {
 		logger.Errorf("Initialization failed. Error: %v.", err)
 		return
 	}
+	onTime, err := NewOnTimeCallback(requestFactory, logger)
+	if err != nil {
+		logger.Errorf("Failed to create on-time handler. Error: %v.", err)
+		return
+	}
+	err = registry.RegisterCommand("/time", onTime)
+	if err != nil {
+		logger.Errorf("Initialization failed. Error: %v.", err)
+		return
+	}
 	onUpdate, err := bot.NewDefaultUpdateCallback(requestFactory, logger, registry)
 	if err != nil {
 		logger.Errorf("Failed to create default update-callback. Error: %v.", err)
@@ -237,6 +288,12 @@ func main() {
 
 	updateProcessor := bot.NewUpdateProcessor(onUpdate, logger)
 	botApp := bot.NewPollingBot(requestFactory, updateProcessor, time.Second, logger)
+	go func() {
+		runErr := botApp.Run(context.Background())
+		if runErr != nil {
+			logger.Errorf("Bot stopped with error. Error: %v.", runErr)
+		}
+	}()
 	defer botApp.Stop()
 	logger.Infof("Bot started. Press Enter to stop.")
 	_, _ = fmt.Scanf("\n")
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -295,6 +296,12 @@ func main() {
 
 	updateProcessor := bot.NewUpdateProcessor(onUpdate, logger)
 	botApp := bot.NewPollingBot(requestFactory, updateProcessor, time.Second, logger)
+	go func() {
+		runErr := botApp.Run(context.Background())
+		if runErr != nil {
+			logger.Errorf("Bot stopped with error. Error: %v.", runErr)
+		}
+	}()
 	defer botApp.Stop()
 	logger.Infof("Bot started. Press Enter to stop.")
 	_, _ = fmt.Scanf("\n")
@@ -13,6 +13,16 @@ const (
 	ENTITY_TYPE_TEXT_LINK   = "text_link"
 )
 
+const (
+	UPDATE_TYPE_MESSAGE              = "message"
+	UPDATE_TYPE_EDITED_MESSAGE       = "edited_message"
+	UPDATE_TYPE_CHANNEL_POST         = "channel_post"
+	UPDATE_TYPE_EDITED_CHANNEL_POST  = "edited_channel_post"
+	UPDATE_TYPE_INLINE_QUERY         = "inline_query"
+	UPDATE_TYPE_CHOSEN_INLINE_RESULT = "chosen_inline_result"
+	UPDATE_TYPE_CALLBACK_QUERY       = "callback_query"
+)
+
 type MessageEntityType struct {
 	Type   string `json:"type"`
 	Offset int64  `json:"offset"`
@@ -181,9 +191,33 @@ type UpdateResultType struct {
 	Updates []UpdateType `json:"result"`
 }
 
+type WebhookInfo struct {
+	URL                          string   `json:"url"`
+	HasCustomCertificate         bool     `json:"has_custom_certificate"`
+	PendingUpdateCount           int64    `json:"pending_update_count"`
+	IPAddress                    string   `json:"ip_address,omitempty"`
+	LastErrorDate                int64    `json:"last_error_date,omitempty"`
+	LastErrorMessage             string   `json:"last_error_message,omitempty"`
+	LastSynchronizationErrorDate int64    `json:"last_synchronization_error_date,omitempty"`
+	MaxConnections               int64    `json:"max_connections,omitempty"`
+	AllowedUpdates               []string `json:"allowed_updates,omitempty"`
+}
+
+type WebhookInfoResult struct {
+	Ok     bool         `json:"ok"`
+	Result *WebhookInfo `json:"result,omitempty"`
+}
+
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int64 `json:"retry_after,omitempty"`
+}
+
+// SendResult is reply of method. Result is nil, if method returns true instead of message (e.g. deleteMessage).
 type SendResult struct {
-	Ok          bool         `json:"ok"`
-	ErrorCode   int64        `json:"error_code,omitempty"`
-	Description *string      `json:"description,omitempty"`
-	Result      *MessageType `json:"result,omitempty"`
+	Ok          bool                `json:"ok"`
+	ErrorCode   int64               `json:"error_code,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+	Result      *MessageType        `json:"result,omitempty"`
 }
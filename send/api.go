@@ -18,6 +18,16 @@ type SendType struct {
 	Parameters  []byte
 	ContentType string
 	Callback    OnSentCallback
+	// ChatID is target chat of request (nil, if request is not bound to a chat).
+	ChatID interface{}
+	// FromChatID is source chat of forward and copy requests. Migration error of such request may be caused by
+	// either of chats, so it's not resent automatically.
+	FromChatID interface{}
+	// NoMigrationResend is set for requests, that refer to existing message of ChatID (edits and deletion).
+	// Message ids are not kept, when group migrates, so such request must not be resent to new chat.
+	NoMigrationResend bool
+	// ResultRequired is set, when request was created with own callback, that needs result of request.
+	ResultRequired bool
 }
 
 type SendResultWithCallback struct {
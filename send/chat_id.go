@@ -0,0 +1,97 @@
+package send
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+const (
+	chat_id_field = "chat_id"
+)
+
+func replaceJSONChatID(parameters []byte, chatID interface{}) ([]byte, custom_error.CustomError) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(parameters, &fields)
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to unmarshal parameters. Error: %v", err)
+	}
+	value, err := json.Marshal(chatID)
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to marshal chat-ID. Error: %v", err)
+	}
+	fields[chat_id_field] = value
+	res, err := json.Marshal(fields)
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to marshal parameters. Error: %v", err)
+	}
+	return res, nil
+}
+
+func replaceMultipartChatID(parameters []byte, boundary string, chatID string) ([]byte, string, custom_error.CustomError) {
+	reader := multipart.NewReader(bytes.NewReader(parameters), boundary)
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", custom_error.MakeErrorf("Failed to read multipart. Error: %v", err)
+		}
+		partWriter, err := writer.CreatePart(part.Header)
+		if err != nil {
+			return nil, "", custom_error.MakeErrorf("Failed to create part. Error: %v", err)
+		}
+		if part.FormName() == chat_id_field {
+			_, err = partWriter.Write([]byte(chatID))
+		} else {
+			_, err = io.Copy(partWriter, part)
+		}
+		if err != nil {
+			return nil, "", custom_error.MakeErrorf("Failed to copy part '%v'. Error: %v", part.FormName(), err)
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		return nil, "", custom_error.MakeErrorf("Failed to close multipart. Error: %v", err)
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// WithChatID returns copy of request, that targets chatID instead of original chat.
+func (s *SendType) WithChatID(chatID interface{}) (*SendType, custom_error.CustomError) {
+	res := *s
+	res.ChatID = chatID
+	mediaType, params, err := mime.ParseMediaType(s.ContentType)
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to parse content type '%v'. Error: %v", s.ContentType, err)
+	}
+	switch mediaType {
+	case content_type_application_json:
+		parameters, customErr := replaceJSONChatID(s.Parameters, chatID)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to replace chat-ID in json request.")
+		}
+		res.Parameters = parameters
+	case "multipart/form-data":
+		chatIDString, customErr := getChatIDString(chatID)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to replace chat-ID in multipart request.")
+		}
+		parameters, contentType, customErr := replaceMultipartChatID(s.Parameters, params["boundary"], chatIDString)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to replace chat-ID in multipart request.")
+		}
+		res.Parameters = parameters
+		res.ContentType = contentType
+	default:
+		return nil, custom_error.MakeErrorf("Unsupported content type: %v", s.ContentType)
+	}
+	return &res, nil
+}
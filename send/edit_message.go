@@ -0,0 +1,71 @@
+package send
+
+import (
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/send/requests"
+)
+
+// NewEditMessageText creates editMessageText request. Like other edit requests, it results in edited message,
+// but for inline messages Telegram returns true only, so receive.SendResult.Result is nil then.
+func (f *RequestFactory) NewEditMessageText(r *requests.EditMessageText, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create edit message text. Request is nil.")
+	}
+	res, customErr := f.newEditSendType(f.editMessageTextURL, &r.MessageTarget, r, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create edit message text.")
+}
+
+func (f *RequestFactory) NewEditMessageCaption(r *requests.EditMessageCaption, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create edit message caption. Request is nil.")
+	}
+	res, customErr := f.newEditSendType(f.editMessageCaptionURL, &r.MessageTarget, r, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create edit message caption.")
+}
+
+func (f *RequestFactory) NewEditMessageReplyMarkup(r *requests.EditMessageReplyMarkup, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create edit message reply markup. Request is nil.")
+	}
+	res, customErr := f.newEditSendType(f.editMessageMarkupURL, &r.MessageTarget, r, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create edit message reply markup.")
+}
+
+func (f *RequestFactory) NewEditMessageMedia(r *requests.EditMessageMedia, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create edit message media. Request is nil.")
+	}
+	if len(r.Media.Type) <= 0 || len(r.Media.Media) <= 0 {
+		return nil, custom_error.MakeErrorf("Failed to create edit message media. Media type and file are required.")
+	}
+	res, customErr := f.newEditSendType(f.editMessageMediaURL, &r.MessageTarget, r, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create edit message media.")
+}
+
+// NewDeleteMessage creates deleteMessage request. Its result is true, so receive.SendResult.Result is nil.
+func (f *RequestFactory) NewDeleteMessage(r *requests.DeleteMessage, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create delete message. Request is nil.")
+	}
+	if r.ChatID == nil || r.MessageID == 0 {
+		return nil, custom_error.MakeErrorf("Failed to create delete message. Chat id and message id are required.")
+	}
+	res, customErr := f.newPostSendType(f.deleteMessageURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to create delete message.")
+	}
+	res[0].NoMigrationResend = true
+	return res, nil
+}
@@ -0,0 +1,50 @@
+package send
+
+import (
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/send/requests"
+)
+
+func (f *RequestFactory) NewForwardMessage(r *requests.ForwardMessage, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create forward message. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.forwardMessageURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to create forward message.")
+	}
+	res[0].FromChatID = r.FromChatID
+	return res, nil
+}
+
+// NewForwardMessages creates forward request per each of messageIDs, so they are forwarded in order.
+// callback is invoked for each of them.
+func (f *RequestFactory) NewForwardMessages(chatID interface{}, fromChatID interface{}, messageIDs []int64, disableNotifications bool, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	res := make([]*SendType, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		forward, customErr := f.NewForwardMessage(&requests.ForwardMessage{
+			ChatID:               chatID,
+			FromChatID:           fromChatID,
+			MessageID:            messageID,
+			DisableNotifications: disableNotifications,
+		}, callback)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to create forward of message id '%d'.", messageID)
+		}
+		res = append(res, forward...)
+	}
+	return res, nil
+}
+
+// NewCopyMessage creates copyMessage request. Its result contains id of the copy only (receive.MessageType.ID).
+func (f *RequestFactory) NewCopyMessage(r *requests.CopyMessage, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create copy message. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.copyMessageURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to create copy message.")
+	}
+	res[0].FromChatID = r.FromChatID
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package send
+
+import (
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/send/requests"
+)
+
+func (f *RequestFactory) NewSendLocation(r *requests.SendLocation, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send location. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendLocationURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send location.")
+}
+
+func (f *RequestFactory) NewSendVenue(r *requests.SendVenue, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send venue. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendVenueURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send venue.")
+}
+
+func (f *RequestFactory) NewSendContact(r *requests.SendContact, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send contact. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendContactURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send contact.")
+}
+
+// NewEditMessageLiveLocation moves live location, sent with LivePeriod, until it expires or is stopped.
+// Result is edited message, or no message for inline messages.
+func (f *RequestFactory) NewEditMessageLiveLocation(r *requests.EditMessageLiveLocation, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create edit live location. Request is nil.")
+	}
+	res, customErr := f.newEditSendType(f.editLiveLocationURL, &r.MessageTarget, r, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create edit live location.")
+}
+
+func (f *RequestFactory) NewStopMessageLiveLocation(r *requests.StopMessageLiveLocation, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create stop live location. Request is nil.")
+	}
+	res, customErr := f.newEditSendType(f.stopLiveLocationURL, &r.MessageTarget, r, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create stop live location.")
+}
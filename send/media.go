@@ -0,0 +1,166 @@
+package send
+
+import (
+	"fmt"
+
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/send/requests"
+)
+
+func addIntField(fields map[string]string, name string, value int64) {
+	if value > 0 {
+		fields[name] = fmt.Sprintf("%d", value)
+	}
+}
+
+func addStringField(fields map[string]string, name string, value string) {
+	if len(value) > 0 {
+		fields[name] = value
+	}
+}
+
+func (f *RequestFactory) newMediaUpload(url string, fieldName string, base *requests.SendFileBase, fields map[string]string, thumbFileName string, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	r := requests.SendFile{
+		SendFileBase:  *base,
+		FieldName:     fieldName,
+		Fields:        fields,
+		ThumbFileName: thumbFileName,
+	}
+	res, customErr := f.newFileUpload(url, &r, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to upload %v.", fieldName)
+}
+
+func (f *RequestFactory) NewUploadAudio(r *requests.UploadAudio, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create upload audio. Request is nil.")
+	}
+	fields := make(map[string]string)
+	addIntField(fields, "duration", r.Duration)
+	addStringField(fields, "performer", r.Performer)
+	addStringField(fields, "title", r.Title)
+	return f.newMediaUpload(f.sendAudioURL, "audio", &r.SendFileBase, fields, r.ThumbFileName, callback)
+}
+
+func (f *RequestFactory) NewSendUploadedAudio(r *requests.SendUploadedAudio, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send uploaded audio. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendAudioURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send uploaded audio.")
+}
+
+func (f *RequestFactory) NewUploadDocument(r *requests.UploadDocument, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create upload document. Request is nil.")
+	}
+	return f.newMediaUpload(f.sendDocumentURL, "document", &r.SendFileBase, nil, r.ThumbFileName, callback)
+}
+
+func (f *RequestFactory) NewSendUploadedDocument(r *requests.SendUploadedDocument, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send uploaded document. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendDocumentURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send uploaded document.")
+}
+
+func (f *RequestFactory) NewUploadVideo(r *requests.UploadVideo, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create upload video. Request is nil.")
+	}
+	fields := make(map[string]string)
+	addIntField(fields, "duration", r.Duration)
+	addIntField(fields, "width", r.Width)
+	addIntField(fields, "height", r.Height)
+	if r.SupportsStreaming {
+		fields["supports_streaming"] = "true"
+	}
+	return f.newMediaUpload(f.sendVideoURL, "video", &r.SendFileBase, fields, r.ThumbFileName, callback)
+}
+
+func (f *RequestFactory) NewSendUploadedVideo(r *requests.SendUploadedVideo, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send uploaded video. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendVideoURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send uploaded video.")
+}
+
+func (f *RequestFactory) NewUploadVoice(r *requests.UploadVoice, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create upload voice. Request is nil.")
+	}
+	fields := make(map[string]string)
+	addIntField(fields, "duration", r.Duration)
+	return f.newMediaUpload(f.sendVoiceURL, "voice", &r.SendFileBase, fields, "", callback)
+}
+
+func (f *RequestFactory) NewSendUploadedVoice(r *requests.SendUploadedVoice, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send uploaded voice. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendVoiceURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send uploaded voice.")
+}
+
+func (f *RequestFactory) NewUploadAnimation(r *requests.UploadAnimation, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create upload animation. Request is nil.")
+	}
+	fields := make(map[string]string)
+	addIntField(fields, "duration", r.Duration)
+	addIntField(fields, "width", r.Width)
+	addIntField(fields, "height", r.Height)
+	return f.newMediaUpload(f.sendAnimationURL, "animation", &r.SendFileBase, fields, r.ThumbFileName, callback)
+}
+
+func (f *RequestFactory) NewSendUploadedAnimation(r *requests.SendUploadedAnimation, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send uploaded animation. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendAnimationURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send uploaded animation.")
+}
+
+func (f *RequestFactory) NewUploadVideoNote(r *requests.UploadVideoNote, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create upload video note. Request is nil.")
+	}
+	fields := make(map[string]string)
+	addIntField(fields, "duration", r.Duration)
+	addIntField(fields, "length", r.Length)
+	base := requests.SendFileBase{
+		Base:     r.Base,
+		FileName: r.FileName,
+	}
+	return f.newMediaUpload(f.sendVideoNoteURL, "video_note", &base, fields, r.ThumbFileName, callback)
+}
+
+func (f *RequestFactory) NewSendUploadedVideoNote(r *requests.SendUploadedVideoNote, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send uploaded video note. Request is nil.")
+	}
+	res, customErr := f.newPostSendType(f.sendVideoNoteURL, r.ChatID, r, content_type_application_json, callback)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send uploaded video note.")
+}
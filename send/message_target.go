@@ -0,0 +1,32 @@
+package send
+
+import (
+	"github.com/coldze/primitives/custom_error"
+	"github.com/coldze/telebot/send/requests"
+)
+
+func validateMessageTarget(target *requests.MessageTarget) custom_error.CustomError {
+	if len(target.InlineMessageID) > 0 {
+		if target.ChatID != nil || target.MessageID != 0 {
+			return custom_error.MakeErrorf("Either inline message id or chat id with message id should be specified.")
+		}
+		return nil
+	}
+	if target.ChatID == nil || target.MessageID == 0 {
+		return custom_error.MakeErrorf("Chat id and message id are required, unless inline message id is specified.")
+	}
+	return nil
+}
+
+func (f *RequestFactory) newEditSendType(url string, target *requests.MessageTarget, message interface{}, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+	customErr := validateMessageTarget(target)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Invalid message target.")
+	}
+	res, customErr := f.newPostSendType(url, target.ChatID, message, content_type_application_json, callback)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to post-send edit.")
+	}
+	res[0].NoMigrationResend = true
+	return res, nil
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"mime/multipart"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/coldze/primitives/custom_error"
 	"github.com/coldze/primitives/logs"
@@ -21,28 +23,42 @@ const (
 )
 
 const (
-	bot_query_fmt = "https://api.telegram.org/bot%s/"
+	DEFAULT_API_URL = "https://api.telegram.org"
+
+	bot_query_fmt = "%s/bot%s/"
 
 	cmd_get_updates             = "%sgetUpdates"
 	cmd_set_web_hook            = "%ssetWebhook"
+	cmd_delete_web_hook         = "%sdeleteWebhook"
+	cmd_get_web_hook_info       = "%sgetWebhookInfo"
 	cmd_get_me                  = "%sgetMe"
 	cmd_send_message            = "%ssendMessage"
 	cmd_forward_message         = "%sforwardMessage"
+	cmd_copy_message            = "%scopyMessage"
 	cmd_send_photo              = "%ssendPhoto"
 	cmd_send_audio              = "%ssendAudio"
 	cmd_send_document           = "%ssendDocument"
 	cmd_send_sticker            = "%ssendSticker"
 	cmd_send_video              = "%ssendVideo"
 	cmd_send_voice              = "%ssendVoice"
+	cmd_send_animation          = "%ssendAnimation"
+	cmd_send_video_note         = "%ssendVideoNote"
 	cmd_send_location           = "%ssendLocation"
 	cmd_send_venue              = "%ssendVenue"
 	cmd_send_contact            = "%ssendContact"
 	cmd_send_chat_action        = "%ssendChatAction"
 	cmd_get_user_profile_photos = "%sgetUserProfilePhotos"
-	cmd_get_file                = "https://api.telegram.org/file/bot%s/%s"
+	cmd_get_file                = "%s/file/bot%s/%s"
 	cmd_kick_chat_member        = "%skickChatMember"
 	cmd_unban_chat_member       = "%sunbanChatMember"
 	cmd_answer_callback_query   = "%sanswerCallbackQuery"
+	cmd_edit_live_location      = "%seditMessageLiveLocation"
+	cmd_stop_live_location      = "%sstopMessageLiveLocation"
+	cmd_edit_message_text       = "%seditMessageText"
+	cmd_edit_message_caption    = "%seditMessageCaption"
+	cmd_edit_message_markup     = "%seditMessageReplyMarkup"
+	cmd_edit_message_media      = "%seditMessageMedia"
+	cmd_delete_message          = "%sdeleteMessage"
 
 	content_type_application_json = "application/json"
 )
@@ -55,6 +71,27 @@ type RequestFactory struct {
 	answerCallbackQueryURL string
 	defaultCallback        OnSentCallback
 	setWebhookURL          string
+	deleteWebhookURL       string
+	getWebhookInfoURL      string
+	sendAudioURL           string
+	sendDocumentURL        string
+	sendVideoURL           string
+	sendVoiceURL           string
+	sendAnimationURL       string
+	sendVideoNoteURL       string
+	sendLocationURL        string
+	sendVenueURL           string
+	sendContactURL         string
+	editLiveLocationURL    string
+	stopLiveLocationURL    string
+	editMessageTextURL     string
+	editMessageCaptionURL  string
+	editMessageMarkupURL   string
+	editMessageMediaURL    string
+	deleteMessageURL       string
+	forwardMessageURL      string
+	copyMessageURL         string
+	sendChatActionURL      string
 }
 
 func writeFieldString(writer *multipart.Writer, fieldName string, value string) custom_error.CustomError {
@@ -77,6 +114,22 @@ func writeFieldBytes(writer *multipart.Writer, fieldName string, value []byte) c
 	return custom_error.MakeErrorf("Failed to write field. Error: %v", err)
 }
 
+func writeFieldFile(writer *multipart.Writer, fieldName string, fileName string) custom_error.CustomError {
+	uploadingFile, err := os.Open(fileName)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to open file. Fieldname: '%v'. Filename: '%v'. Error: %v", fieldName, fileName, err)
+	}
+	defer uploadingFile.Close()
+	fieldWriter, err := writer.CreateFormFile(fieldName, fileName)
+	if err != nil {
+		return custom_error.MakeErrorf("Failed to create form from file. Fieldname: '%v'. Filename: '%v'. Error: %v", fieldName, fileName, err)
+	}
+	if _, err = io.Copy(fieldWriter, uploadingFile); err != nil {
+		return custom_error.MakeErrorf("Failed to create form from file. Fieldname: '%v'. Filename: '%v'. Error: %v", fieldName, fileName, err)
+	}
+	return nil
+}
+
 func (f *RequestFactory) NewSendRaw(url string, message interface{}) ([]*SendType, custom_error.CustomError) {
 	request, err := json.Marshal(message)
 	if err != nil {
@@ -90,30 +143,32 @@ func (f *RequestFactory) NewSendRaw(url string, message interface{}) ([]*SendTyp
 	}, nil
 }
 
-func (f *RequestFactory) newPostSendType(url string, message interface{}, contentType string, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+func (f *RequestFactory) newPostSendType(url string, chatID interface{}, message interface{}, contentType string, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
 	requestMessage, err := json.Marshal(message)
 	if err != nil {
 		return nil, custom_error.MakeErrorf("Failed to marshal message. Error: %v", err)
 	}
-	res, customErr := f.newPostSendTypeBytes(url, requestMessage, contentType, callback)
+	res, customErr := f.newPostSendTypeBytes(url, chatID, requestMessage, contentType, callback)
 	if customErr == nil {
 		return res, nil
 	}
 	return nil, custom_error.NewErrorf(customErr, "Failed to post-send")
 }
 
-func (f *RequestFactory) newPostSendTypeBytes(url string, message []byte, contentType string, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
+func (f *RequestFactory) newPostSendTypeBytes(url string, chatID interface{}, message []byte, contentType string, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
 	cb := callback
 	if cb == nil {
 		cb = f.defaultCallback
 	}
 	return []*SendType{
 		&SendType{
-			URL:         url,
-			Parameters:  message,
-			Type:        SEND_TYPE_POST,
-			ContentType: contentType,
-			Callback:    cb,
+			URL:            url,
+			Parameters:     message,
+			Type:           SEND_TYPE_POST,
+			ContentType:    contentType,
+			Callback:       cb,
+			ChatID:         chatID,
+			ResultRequired: callback != nil,
 		},
 	}, nil
 }
@@ -143,17 +198,15 @@ func (f *RequestFactory) newFileUpload(url string, r *requests.SendFile, callbac
 	if len(r.FileName) <= 0 {
 		return nil, custom_error.MakeErrorf("No file to upload")
 	}
-	uploadingFile, err := os.Open(r.FileName)
-	if err != nil {
-		return nil, custom_error.MakeErrorf("Failed to open file. Fieldname: '%v'. Filename: '%v'. Error: %v", r.FieldName, r.FileName, err)
-	}
-	defer uploadingFile.Close()
-	fieldWriter, err := bufferWriter.CreateFormFile(r.FieldName, r.FileName)
-	if err != nil {
-		return nil, custom_error.MakeErrorf("Failed to create form from file. Fieldname: '%v'. Filename: '%v'. Error: %v", r.FieldName, r.FileName, err)
+	customErr = writeFieldFile(bufferWriter, r.FieldName, r.FileName)
+	if customErr != nil {
+		return nil, custom_error.NewErrorf(customErr, "Failed to write file.")
 	}
-	if _, err = io.Copy(fieldWriter, uploadingFile); err != nil {
-		return nil, custom_error.MakeErrorf("Failed to create form from file. Fieldname: '%v'. Filename: '%v'. Error: %v", r.FieldName, r.FileName, err)
+	if len(r.ThumbFileName) > 0 {
+		customErr = writeFieldFile(bufferWriter, "thumb", r.ThumbFileName)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write thumb.")
+		}
 	}
 	getChatIDString(r.ChatID)
 
@@ -162,9 +215,11 @@ func (f *RequestFactory) newFileUpload(url string, r *requests.SendFile, callbac
 		return nil, custom_error.NewErrorf(customErr, "Failed to write chat_id.")
 	}
 
-	customErr = writeFieldString(bufferWriter, "caption", r.Caption)
-	if customErr != nil {
-		return nil, custom_error.NewErrorf(customErr, "Failed to write caption.")
+	if len(r.Caption) > 0 {
+		customErr = writeFieldString(bufferWriter, "caption", r.Caption)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write caption.")
+		}
 	}
 
 	replyMarkupSerialized, err := json.Marshal(r.ReplyMarkup)
@@ -187,8 +242,20 @@ func (f *RequestFactory) newFileUpload(url string, r *requests.SendFile, callbac
 		return nil, custom_error.NewErrorf(customErr, "Failed to write reply_markup.")
 	}
 
+	fieldNames := make([]string, 0, len(r.Fields))
+	for name := range r.Fields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+	for _, name := range fieldNames {
+		customErr = writeFieldString(bufferWriter, name, r.Fields[name])
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write %v.", name)
+		}
+	}
+
 	bufferWriter.Close()
-	res, customErr := f.newPostSendTypeBytes(url, buf.Bytes(), bufferWriter.FormDataContentType(), callback)
+	res, customErr := f.newPostSendTypeBytes(url, r.ChatID, buf.Bytes(), bufferWriter.FormDataContentType(), callback)
 	if customErr == nil {
 		return res, nil
 	}
@@ -199,7 +266,7 @@ func (f *RequestFactory) NewSendSticker(r *requests.SendSticker, callback OnSent
 	if r == nil {
 		return nil, custom_error.MakeErrorf("Failed to send sticker. Request is nil.")
 	}
-	res, customErr := f.newPostSendType(f.sendStickerURL, r, content_type_application_json, callback)
+	res, customErr := f.newPostSendType(f.sendStickerURL, r.ChatID, r, content_type_application_json, callback)
 	if customErr == nil {
 		return res, nil
 	}
@@ -207,7 +274,7 @@ func (f *RequestFactory) NewSendSticker(r *requests.SendSticker, callback OnSent
 }
 
 func (f *RequestFactory) NewUnsubscribe() ([]*SendType, custom_error.CustomError) {
-	res, customErr := f.NewSubscribe("", "")
+	res, customErr := f.NewDeleteWebhook(false)
 	if customErr == nil {
 		return res, nil
 	}
@@ -215,15 +282,32 @@ func (f *RequestFactory) NewUnsubscribe() ([]*SendType, custom_error.CustomError
 }
 
 func (f *RequestFactory) NewSubscribe(url string, sslPublicKey string) ([]*SendType, custom_error.CustomError) {
+	return f.NewSubscribeWithSecret(url, sslPublicKey, "")
+}
+
+// NewSubscribeWithSecret creates setWebhook request. If secretToken is not empty, Telegram sends it
+// in X-Telegram-Bot-Api-Secret-Token header of every web-hook request.
+func (f *RequestFactory) NewSubscribeWithSecret(url string, sslPublicKey string, secretToken string) ([]*SendType, custom_error.CustomError) {
+	return f.NewSetWebhook(&requests.SetWebhook{
+		URL:         url,
+		Certificate: sslPublicKey,
+		SecretToken: secretToken,
+	})
+}
+
+func (f *RequestFactory) NewSetWebhook(r *requests.SetWebhook) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create set webhook. Request is nil.")
+	}
 	var buf bytes.Buffer
 	bufferWriter := multipart.NewWriter(&buf)
-	if len(sslPublicKey) > 0 {
-		sslCertificate, err := os.Open(sslPublicKey)
+	if len(r.Certificate) > 0 {
+		sslCertificate, err := os.Open(r.Certificate)
 		if err != nil {
-			return nil, custom_error.MakeErrorf("Failed to open file with public-key: '%v'. Error: %v", sslPublicKey, err)
+			return nil, custom_error.MakeErrorf("Failed to open file with public-key: '%v'. Error: %v", r.Certificate, err)
 		}
 		defer sslCertificate.Close()
-		fieldWriter, err := bufferWriter.CreateFormFile("certificate", sslPublicKey)
+		fieldWriter, err := bufferWriter.CreateFormFile("certificate", r.Certificate)
 		if err != nil {
 			return nil, custom_error.MakeErrorf("Failed to create field certificate from public-key file. Error: %v", err)
 		}
@@ -231,18 +315,73 @@ func (f *RequestFactory) NewSubscribe(url string, sslPublicKey string) ([]*SendT
 			return nil, custom_error.MakeErrorf("Failed to write certificate. Error: %v", err)
 		}
 	}
-	customErr := writeFieldString(bufferWriter, "url", url)
+	customErr := writeFieldString(bufferWriter, "url", r.URL)
 	if customErr != nil {
 		return nil, custom_error.NewErrorf(customErr, "Failed to write url field.")
 	}
+	if len(r.IPAddress) > 0 {
+		customErr = writeFieldString(bufferWriter, "ip_address", r.IPAddress)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write ip_address field.")
+		}
+	}
+	if r.MaxConnections > 0 {
+		customErr = writeFieldString(bufferWriter, "max_connections", fmt.Sprintf("%d", r.MaxConnections))
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write max_connections field.")
+		}
+	}
+	if r.AllowedUpdates != nil {
+		allowedUpdates, err := json.Marshal(r.AllowedUpdates)
+		if err != nil {
+			return nil, custom_error.MakeErrorf("Failed to marshal allowed updates. Error: %v", err)
+		}
+		customErr = writeFieldBytes(bufferWriter, "allowed_updates", allowedUpdates)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write allowed_updates field.")
+		}
+	}
+	if r.DropPendingUpdates {
+		customErr = writeFieldString(bufferWriter, "drop_pending_updates", "true")
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write drop_pending_updates field.")
+		}
+	}
+	if len(r.SecretToken) > 0 {
+		customErr = writeFieldString(bufferWriter, "secret_token", r.SecretToken)
+		if customErr != nil {
+			return nil, custom_error.NewErrorf(customErr, "Failed to write secret_token field.")
+		}
+	}
 	bufferWriter.Close()
-	res, customErr := f.newPostSendTypeBytes(f.setWebhookURL, buf.Bytes(), bufferWriter.FormDataContentType(), nil)
+	res, customErr := f.newPostSendTypeBytes(f.setWebhookURL, nil, buf.Bytes(), bufferWriter.FormDataContentType(), nil)
 	if customErr == nil {
 		return res, nil
 	}
 	return nil, custom_error.NewErrorf(customErr, "Failed to subscribe.")
 }
 
+// NewDeleteWebhook creates deleteWebhook request. If dropPendingUpdates is set, updates, that were not delivered yet, are dropped.
+func (f *RequestFactory) NewDeleteWebhook(dropPendingUpdates bool) ([]*SendType, custom_error.CustomError) {
+	val := requests.DeleteWebhook{
+		DropPendingUpdates: dropPendingUpdates,
+	}
+	res, customErr := f.newPostSendType(f.deleteWebhookURL, nil, val, content_type_application_json, nil)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create delete webhook.")
+}
+
+// NewGetWebhookInfo creates getWebhookInfo request. Its result is receive.WebhookInfo, not a message.
+func (f *RequestFactory) NewGetWebhookInfo() ([]*SendType, custom_error.CustomError) {
+	res, customErr := f.newPostSendType(f.getWebhookInfoURL, nil, struct{}{}, content_type_application_json, nil)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create get webhook info.")
+}
+
 func (f *RequestFactory) NewUploadPhoto(r *requests.SendFileBase, callback OnSentCallback) ([]*SendType, custom_error.CustomError) {
 	if r == nil {
 		return nil, custom_error.MakeErrorf("Failed to create upload photo. Request is nil.")
@@ -262,7 +401,7 @@ func (f *RequestFactory) NewSendUploadedPhoto(r *requests.SendUploadedPhoto, cal
 	if r == nil {
 		return nil, custom_error.MakeErrorf("Failed to create send uploaded photo. Request is nil.")
 	}
-	res, customErr := f.newPostSendType(f.sendPhotoURL, r, content_type_application_json, callback)
+	res, customErr := f.newPostSendType(f.sendPhotoURL, r.ChatID, r, content_type_application_json, callback)
 	if customErr == nil {
 		return res, nil
 	}
@@ -273,7 +412,7 @@ func (f *RequestFactory) NewSendMessage(r *requests.SendMessage, callback OnSent
 	if r == nil {
 		return nil, custom_error.MakeErrorf("Failed to create send message. Request is nil.")
 	}
-	res, customErr := f.newPostSendType(f.sendMessageURL, r, content_type_application_json, callback)
+	res, customErr := f.newPostSendType(f.sendMessageURL, r.ChatID, r, content_type_application_json, callback)
 	if customErr == nil {
 		return res, nil
 	}
@@ -281,19 +420,39 @@ func (f *RequestFactory) NewSendMessage(r *requests.SendMessage, callback OnSent
 }
 
 func (f *RequestFactory) NewAnswerCallbackQuery(message *requests.AnswerCallbackQuery) ([]*SendType, custom_error.CustomError) {
-	res, customErr := f.newPostSendType(f.answerCallbackQueryURL, message, content_type_application_json, nil)
+	res, customErr := f.newPostSendType(f.answerCallbackQueryURL, nil, message, content_type_application_json, nil)
 	if customErr == nil {
 		return res, nil
 	}
 	return nil, custom_error.NewErrorf(customErr, "Failed to create answer callback query.")
 }
 
+func (f *RequestFactory) NewSendChatAction(r *requests.SendChatAction) ([]*SendType, custom_error.CustomError) {
+	if r == nil {
+		return nil, custom_error.MakeErrorf("Failed to create send chat action. Request is nil.")
+	}
+	if len(r.Action) <= 0 {
+		return nil, custom_error.MakeErrorf("Failed to create send chat action. Action is empty.")
+	}
+	res, customErr := f.newPostSendType(f.sendChatActionURL, r.ChatID, r, content_type_application_json, nil)
+	if customErr == nil {
+		return res, nil
+	}
+	return nil, custom_error.NewErrorf(customErr, "Failed to create send chat action.")
+}
+
 func (f *RequestFactory) NewGetUpdates(offset int64, limit int64, timeout int64) ([]*SendType, custom_error.CustomError) {
+	return f.NewGetUpdatesWithAllowed(offset, limit, timeout, nil)
+}
+
+// NewGetUpdatesWithAllowed creates get-updates request. Timeout is in seconds, zero means short polling. Empty allowedUpdates means all update types.
+func (f *RequestFactory) NewGetUpdatesWithAllowed(offset int64, limit int64, timeout int64, allowedUpdates []string) ([]*SendType, custom_error.CustomError) {
 	val := send_requests.GetUpdates{
-		Offset:  offset,
-		Limit:   limit,
-		Timeout: timeout}
-	res, customErr := f.newPostSendType(f.getUpdatesURL, val, content_type_application_json, nil)
+		Offset:         offset,
+		Limit:          limit,
+		Timeout:        timeout,
+		AllowedUpdates: allowedUpdates}
+	res, customErr := f.newPostSendType(f.getUpdatesURL, nil, val, content_type_application_json, nil)
 	if customErr == nil {
 		return res, nil
 	}
@@ -305,14 +464,40 @@ func (f *RequestFactory) String() string {
 }
 
 func NewRequestFactory(botToken string, logger logs.Logger) *RequestFactory {
-	botRequestUrl := fmt.Sprintf(bot_query_fmt, botToken)
+	return NewRequestFactoryWithURL(DEFAULT_API_URL, botToken, logger)
+}
+
+// NewRequestFactoryWithURL creates factory, that targets Bot API server at apiURL (e.g. self-hosted server or local fake).
+func NewRequestFactoryWithURL(apiURL string, botToken string, logger logs.Logger) *RequestFactory {
+	botRequestUrl := fmt.Sprintf(bot_query_fmt, strings.TrimRight(apiURL, "/"), botToken)
 	var factory RequestFactory
 	factory.sendMessageURL = fmt.Sprintf(cmd_send_message, botRequestUrl)
 	factory.sendStickerURL = fmt.Sprintf(cmd_send_sticker, botRequestUrl)
 	factory.getUpdatesURL = fmt.Sprintf(cmd_get_updates, botRequestUrl)
 	factory.setWebhookURL = fmt.Sprintf(cmd_set_web_hook, botRequestUrl)
+	factory.deleteWebhookURL = fmt.Sprintf(cmd_delete_web_hook, botRequestUrl)
+	factory.getWebhookInfoURL = fmt.Sprintf(cmd_get_web_hook_info, botRequestUrl)
 	factory.sendPhotoURL = fmt.Sprintf(cmd_send_photo, botRequestUrl)
 	factory.answerCallbackQueryURL = fmt.Sprintf(cmd_answer_callback_query, botRequestUrl)
+	factory.sendAudioURL = fmt.Sprintf(cmd_send_audio, botRequestUrl)
+	factory.sendDocumentURL = fmt.Sprintf(cmd_send_document, botRequestUrl)
+	factory.sendVideoURL = fmt.Sprintf(cmd_send_video, botRequestUrl)
+	factory.sendVoiceURL = fmt.Sprintf(cmd_send_voice, botRequestUrl)
+	factory.sendAnimationURL = fmt.Sprintf(cmd_send_animation, botRequestUrl)
+	factory.sendVideoNoteURL = fmt.Sprintf(cmd_send_video_note, botRequestUrl)
+	factory.sendLocationURL = fmt.Sprintf(cmd_send_location, botRequestUrl)
+	factory.sendVenueURL = fmt.Sprintf(cmd_send_venue, botRequestUrl)
+	factory.sendContactURL = fmt.Sprintf(cmd_send_contact, botRequestUrl)
+	factory.editLiveLocationURL = fmt.Sprintf(cmd_edit_live_location, botRequestUrl)
+	factory.stopLiveLocationURL = fmt.Sprintf(cmd_stop_live_location, botRequestUrl)
+	factory.editMessageTextURL = fmt.Sprintf(cmd_edit_message_text, botRequestUrl)
+	factory.editMessageCaptionURL = fmt.Sprintf(cmd_edit_message_caption, botRequestUrl)
+	factory.editMessageMarkupURL = fmt.Sprintf(cmd_edit_message_markup, botRequestUrl)
+	factory.editMessageMediaURL = fmt.Sprintf(cmd_edit_message_media, botRequestUrl)
+	factory.deleteMessageURL = fmt.Sprintf(cmd_delete_message, botRequestUrl)
+	factory.forwardMessageURL = fmt.Sprintf(cmd_forward_message, botRequestUrl)
+	factory.copyMessageURL = fmt.Sprintf(cmd_copy_message, botRequestUrl)
+	factory.sendChatActionURL = fmt.Sprintf(cmd_send_chat_action, botRequestUrl)
 
 	factory.defaultCallback = func(result *receive.SendResult, err custom_error.CustomError) {
 		if err != nil {
@@ -0,0 +1,11 @@
+package requests
+
+// CopyMessage sends copy of message without link to original one. Caption, if set, replaces original caption
+// (empty string removes it).
+type CopyMessage struct {
+	Base
+	FromChatID interface{} `json:"from_chat_id"`
+	MessageID  int64       `json:"message_id"`
+	Caption    *string     `json:"caption,omitempty"`
+	ParseMode  string      `json:"parse_mode,omitempty"`
+}
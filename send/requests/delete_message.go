@@ -0,0 +1,6 @@
+package requests
+
+type DeleteMessage struct {
+	ChatID    interface{} `json:"chat_id"`
+	MessageID int64       `json:"message_id"`
+}
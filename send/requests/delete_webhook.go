@@ -0,0 +1,5 @@
+package requests
+
+type DeleteWebhook struct {
+	DropPendingUpdates bool `json:"drop_pending_updates,omitempty"`
+}
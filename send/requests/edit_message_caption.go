@@ -0,0 +1,8 @@
+package requests
+
+type EditMessageCaption struct {
+	MessageTarget
+	Caption     string      `json:"caption,omitempty"`
+	ParseMode   string      `json:"parse_mode,omitempty"`
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+}
@@ -0,0 +1,13 @@
+package requests
+
+type EditMessageLiveLocation struct {
+	MessageTarget
+	Latitude    float64     `json:"latitude"`
+	Longitude   float64     `json:"longitude"`
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+}
+
+type StopMessageLiveLocation struct {
+	MessageTarget
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+}
@@ -0,0 +1,23 @@
+package requests
+
+const (
+	INPUT_MEDIA_ANIMATION = "animation"
+	INPUT_MEDIA_DOCUMENT  = "document"
+	INPUT_MEDIA_AUDIO     = "audio"
+	INPUT_MEDIA_PHOTO     = "photo"
+	INPUT_MEDIA_VIDEO     = "video"
+)
+
+// InputMedia is new content of message. Media is file_id of uploaded file or HTTP URL.
+type InputMedia struct {
+	Type      string `json:"type"`
+	Media     string `json:"media"`
+	Caption   string `json:"caption,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+type EditMessageMedia struct {
+	MessageTarget
+	Media       InputMedia  `json:"media"`
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+}
@@ -0,0 +1,7 @@
+package requests
+
+// EditMessageReplyMarkup replaces inline keyboard of message. Empty ReplyMarkup removes keyboard.
+type EditMessageReplyMarkup struct {
+	MessageTarget
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+}
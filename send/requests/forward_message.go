@@ -0,0 +1,8 @@
+package requests
+
+type ForwardMessage struct {
+	ChatID               interface{} `json:"chat_id"`
+	FromChatID           interface{} `json:"from_chat_id"`
+	MessageID            int64       `json:"message_id"`
+	DisableNotifications bool        `json:"disable_notification,omitempty"`
+}
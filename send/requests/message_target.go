@@ -0,0 +1,9 @@
+package requests
+
+// MessageTarget addresses message, that is edited: either ChatID and MessageID, or InlineMessageID
+// for messages, sent via inline mode.
+type MessageTarget struct {
+	ChatID          interface{} `json:"chat_id,omitempty"`
+	MessageID       int64       `json:"message_id,omitempty"`
+	InlineMessageID string      `json:"inline_message_id,omitempty"`
+}
@@ -0,0 +1,19 @@
+package requests
+
+// SendUploadedAnimation re-sends animation (GIF or H.264/MPEG-4 AVC video without sound), that was already uploaded, by its file_id.
+type SendUploadedAnimation struct {
+	Base
+	Animation string `json:"animation"`
+	Caption   string `json:"caption,omitempty"`
+	Duration  int64  `json:"duration,omitempty"`
+	Width     int64  `json:"width,omitempty"`
+	Height    int64  `json:"height,omitempty"`
+}
+
+type UploadAnimation struct {
+	SendFileBase
+	Duration      int64
+	Width         int64
+	Height        int64
+	ThumbFileName string
+}
@@ -0,0 +1,19 @@
+package requests
+
+// SendUploadedAudio re-sends audio, that was already uploaded, by its file_id.
+type SendUploadedAudio struct {
+	Base
+	Audio     string `json:"audio"`
+	Caption   string `json:"caption,omitempty"`
+	Duration  int64  `json:"duration,omitempty"`
+	Performer string `json:"performer,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+type UploadAudio struct {
+	SendFileBase
+	Duration      int64
+	Performer     string
+	Title         string
+	ThumbFileName string
+}
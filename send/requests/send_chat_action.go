@@ -0,0 +1,20 @@
+package requests
+
+const (
+	CHAT_ACTION_TYPING            = "typing"
+	CHAT_ACTION_UPLOAD_PHOTO      = "upload_photo"
+	CHAT_ACTION_RECORD_VIDEO      = "record_video"
+	CHAT_ACTION_UPLOAD_VIDEO      = "upload_video"
+	CHAT_ACTION_RECORD_VOICE      = "record_voice"
+	CHAT_ACTION_UPLOAD_VOICE      = "upload_voice"
+	CHAT_ACTION_UPLOAD_DOCUMENT   = "upload_document"
+	CHAT_ACTION_FIND_LOCATION     = "find_location"
+	CHAT_ACTION_RECORD_VIDEO_NOTE = "record_video_note"
+	CHAT_ACTION_UPLOAD_VIDEO_NOTE = "upload_video_note"
+)
+
+// SendChatAction shows status (e.g. "typing...") in chat for 5 seconds or until bot sends message.
+type SendChatAction struct {
+	ChatID interface{} `json:"chat_id"`
+	Action string      `json:"action"`
+}
@@ -0,0 +1,8 @@
+package requests
+
+type SendContact struct {
+	Base
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+}
@@ -0,0 +1,13 @@
+package requests
+
+// SendUploadedDocument re-sends document, that was already uploaded, by its file_id.
+type SendUploadedDocument struct {
+	Base
+	Document string `json:"document"`
+	Caption  string `json:"caption,omitempty"`
+}
+
+type UploadDocument struct {
+	SendFileBase
+	ThumbFileName string
+}
@@ -3,4 +3,8 @@ package requests
 type SendFile struct {
 	SendFileBase
 	FieldName string
+	// Fields are additional form fields of request (e.g. duration, title).
+	Fields map[string]string
+	// ThumbFileName is path to thumbnail, that is uploaded along with file.
+	ThumbFileName string
 }
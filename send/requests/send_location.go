@@ -0,0 +1,10 @@
+package requests
+
+// SendLocation sends point on map. LivePeriod (60-86400 seconds) makes location live, so it can be updated
+// with EditMessageLiveLocation.
+type SendLocation struct {
+	Base
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	LivePeriod int64   `json:"live_period,omitempty"`
+}
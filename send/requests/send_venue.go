@@ -0,0 +1,10 @@
+package requests
+
+type SendVenue struct {
+	Base
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	Title        string  `json:"title"`
+	Address      string  `json:"address"`
+	FoursquareID string  `json:"foursquare_id,omitempty"`
+}
@@ -0,0 +1,21 @@
+package requests
+
+// SendUploadedVideo re-sends video, that was already uploaded, by its file_id.
+type SendUploadedVideo struct {
+	Base
+	Video             string `json:"video"`
+	Caption           string `json:"caption,omitempty"`
+	Duration          int64  `json:"duration,omitempty"`
+	Width             int64  `json:"width,omitempty"`
+	Height            int64  `json:"height,omitempty"`
+	SupportsStreaming bool   `json:"supports_streaming,omitempty"`
+}
+
+type UploadVideo struct {
+	SendFileBase
+	Duration          int64
+	Width             int64
+	Height            int64
+	SupportsStreaming bool
+	ThumbFileName     string
+}
@@ -0,0 +1,17 @@
+package requests
+
+// SendUploadedVideoNote re-sends video note, that was already uploaded, by its file_id. Video notes have no caption.
+type SendUploadedVideoNote struct {
+	Base
+	VideoNote string `json:"video_note"`
+	Duration  int64  `json:"duration,omitempty"`
+	Length    int64  `json:"length,omitempty"`
+}
+
+type UploadVideoNote struct {
+	Base
+	FileName      string
+	Duration      int64
+	Length        int64
+	ThumbFileName string
+}
@@ -0,0 +1,14 @@
+package requests
+
+// SendUploadedVoice re-sends voice message, that was already uploaded, by its file_id.
+type SendUploadedVoice struct {
+	Base
+	Voice    string `json:"voice"`
+	Caption  string `json:"caption,omitempty"`
+	Duration int64  `json:"duration,omitempty"`
+}
+
+type UploadVoice struct {
+	SendFileBase
+	Duration int64
+}
@@ -0,0 +1,12 @@
+package requests
+
+// SetWebhook is sent as multipart form. Certificate is path to public key, that is uploaded for self-signed certificates.
+type SetWebhook struct {
+	URL                string
+	Certificate        string
+	IPAddress          string
+	MaxConnections     int64
+	AllowedUpdates     []string
+	DropPendingUpdates bool
+	SecretToken        string
+}
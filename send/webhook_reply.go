@@ -0,0 +1,61 @@
+package send
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"github.com/coldze/primitives/custom_error"
+)
+
+const (
+	method_field = "method"
+)
+
+// CanReplyInline tells, if request can be returned in web-hook response body: it has to be json request without
+// file uploads, which result is not required, since Telegram doesn't report result of such requests.
+func (s *SendType) CanReplyInline() bool {
+	if s.Type != SEND_TYPE_POST || s.ResultRequired {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(s.ContentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == content_type_application_json && len(s.GetMethod()) > 0
+}
+
+// GetMethod returns name of API method, request is sent to (e.g. sendMessage).
+func (s *SendType) GetMethod() string {
+	url := strings.SplitN(s.URL, "?", 2)[0]
+	index := strings.LastIndex(url, "/")
+	if index < 0 {
+		return ""
+	}
+	return url[index+1:]
+}
+
+// NewWebHookReply returns body of web-hook response, that makes Telegram execute request.
+func (s *SendType) NewWebHookReply() ([]byte, custom_error.CustomError) {
+	if !s.CanReplyInline() {
+		return nil, custom_error.MakeErrorf("Request to '%v' can't be sent as web-hook reply.", s.GetMethod())
+	}
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(s.Parameters, &fields)
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to unmarshal parameters. Error: %v", err)
+	}
+	if fields == nil {
+		fields = make(map[string]json.RawMessage)
+	}
+	method, err := json.Marshal(s.GetMethod())
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to marshal method. Error: %v", err)
+	}
+	fields[method_field] = method
+	res, err := json.Marshal(fields)
+	if err != nil {
+		return nil, custom_error.MakeErrorf("Failed to marshal web-hook reply. Error: %v", err)
+	}
+	return res, nil
+}